@@ -11,7 +11,7 @@ import View from 'ol/View.js';
 import {Vector as VectorSource} from 'ol/source';
 import Point from 'ol/geom/Point';
 import {Feature} from 'ol';
-import {transform} from 'ol/proj';
+import {transform, transformExtent} from 'ol/proj';
 import Overlay from 'ol/Overlay';
 import {ScaleLine, defaults as defaultControls} from 'ol/control';
 
@@ -285,6 +285,12 @@ var histPositionsLayer = new VectorLayer({
 function eventMsgHandler(event) {
 
     var obj = JSON.parse(event.data);
+
+    // Errors in response to a subscribe message, see `sendSubscription`
+    if (!obj.VP) {
+      console.log(obj.error);
+      return
+    }
     
     // Create a UniqueID for each Bus, Train, etc based on Vehicle ID and route
     // some duplicate Vehicle IDs in fleet, not sure why, concat w. route resolves
@@ -314,6 +320,24 @@ function eventMsgHandler(event) {
     objSource.addFeature(loc)
 }
 
+// Subscribe to vehicles in view only, s.t. the Locations API filters the feed
+// server-side; re-sent whenever the map is panned or zoomed
+function sendSubscription() {
+
+  if (!window.ws || window.ws.readyState !== WebSocket.OPEN) {
+    return
+  }
+
+  var extent = transformExtent(
+    map.getView().calculateExtent(map.getSize()), 'EPSG:3857', 'EPSG:4326'
+  );
+
+  // [min lat, min lng, max lat, max lng]
+  window.ws.send(JSON.stringify({
+    type: "subscribe", bbox: [extent[1], extent[0], extent[3], extent[2]]
+  }));
+}
+
 // Using the sockets to source data onto the map gets expensive w. certain
 // selections; toggle layer off also closes websocket s.t NO events are 
 // processed until (re)connect
@@ -333,6 +357,7 @@ document.getElementById("live-toggle").addEventListener("click", function() {
   livePositionsLayer.setVisible(true);
   window.ws = new WebSocket("wss://" + api_host + "/live/locations/");
   window.ws.onmessage = eventMsgHandler
+  window.ws.onopen = sendSubscription
 });
 
 document.getElementById("routes-toggle").addEventListener("click", function() {
@@ -394,6 +419,8 @@ const map = new Map({
 });
 
 // Apply Map Level Handlers
+map.on('moveend', sendSubscription);
+
 map.on('pointermove', function(event) {
   // Handle for Highlighting 
   overlay.setPosition(undefined);
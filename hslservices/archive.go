@@ -0,0 +1,173 @@
+package hsldatabridge
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ArchiveRecord - A single line of a recorded feed, see `ArchiveWriter`
+type ArchiveRecord struct {
+	Topic    string `json:"topic"`
+	Payload  string `json:"payload"` // Message body as received, kept as a string in case it's not valid JSON
+	Received int64  `json:"recv"`    // Time the message was received, UTC milliseconds
+}
+
+// ArchiveWriter - Writes staged messages to gzip compressed NDJSON files in dir,
+// starting a new file once the current one reaches maxBytes (uncompressed) or
+// maxAge. Files are named by the time they were opened, e.g.
+//
+// hfp-20210514T050000.123.ndjson.gz
+type ArchiveWriter struct {
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+
+	f      *os.File
+	gz     *gzip.Writer
+	n      int64
+	opened time.Time
+}
+
+// NewArchiveWriter - creates dir if needed, the first file is opened on the
+// first call to Write
+func NewArchiveWriter(dir string, maxBytes int64, maxAge time.Duration) (*ArchiveWriter, error) {
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &ArchiveWriter{
+		dir:      dir,
+		maxBytes: maxBytes,
+		maxAge:   maxAge,
+	}, nil
+}
+
+// Write - appends a single message to the current file, rotating first if
+// the file is full or too old
+func (w *ArchiveWriter) Write(msg *StagedMessage) error {
+
+	line, err := json.Marshal(&ArchiveRecord{
+		Topic:    msg.Topic,
+		Payload:  string(msg.Payload),
+		Received: msg.Received.UnixNano() / int64(time.Millisecond),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if w.f != nil && (w.n >= w.maxBytes || time.Since(w.opened) >= w.maxAge) {
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+
+	if w.f == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.gz.Write(append(line, '\n'))
+	w.n += int64(n)
+
+	return err
+}
+
+// open - opens a new archive file
+func (w *ArchiveWriter) open() error {
+
+	now := time.Now().UTC()
+
+	f, err := os.Create(
+		filepath.Join(w.dir, fmt.Sprintf("hfp-%s.ndjson.gz", now.Format("20060102T150405.000"))),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	w.f, w.gz, w.n, w.opened = f, gzip.NewWriter(f), 0, now
+	return nil
+}
+
+// Close - flushes and closes the current file, the next call to Write opens
+// a new file
+func (w *ArchiveWriter) Close() error {
+
+	if w.f == nil {
+		return nil
+	}
+
+	err := w.gz.Close()
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+
+	w.f, w.gz = nil, nil
+	return err
+}
+
+// ArchiveReader - Reads staged messages back from a file written by ArchiveWriter
+type ArchiveReader struct {
+	f  *os.File
+	gz *gzip.Reader
+	sc *bufio.Scanner
+}
+
+// OpenArchive - opens a single archive file for reading
+func OpenArchive(path string) (*ArchiveReader, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	// NOTE: Messages are typically < 1KB, allow for much larger
+	sc := bufio.NewScanner(gz)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	return &ArchiveReader{f: f, gz: gz, sc: sc}, nil
+}
+
+// Next - returns the next message in the archive, io.EOF at the end of
+// the file
+func (r *ArchiveReader) Next() (*StagedMessage, error) {
+
+	if !r.sc.Scan() {
+		if err := r.sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	rec := &ArchiveRecord{}
+	if err := json.Unmarshal(r.sc.Bytes(), rec); err != nil {
+		return nil, err
+	}
+
+	return &StagedMessage{
+		Topic:    rec.Topic,
+		Payload:  []byte(rec.Payload),
+		Received: time.Unix(0, rec.Received*int64(time.Millisecond)),
+	}, nil
+}
+
+// Close - closes the underlying file
+func (r *ArchiveReader) Close() error {
+	r.gz.Close()
+	return r.f.Close()
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	hsl "github.com/dmw2151/hsldatabridge"
+	log "github.com/sirupsen/logrus"
+)
+
+const usage = `Inspect and re-drive MQTT messages that failed to decode
+
+usage:
+  deadletter list [-n 20] [-after <ID>]    List entries, oldest first
+  deadletter errors                        Count entries by error type
+  deadletter redrive [-type <errtype>] [-sinks redis]
+                                           Decode entries again and write them to the sinks
+`
+
+var ctx = context.Background()
+
+func init() {
+	// Set Logging Config
+	log.SetOutput(os.Stderr)
+	log.SetLevel(log.WarnLevel)
+
+	log.SetFormatter(&log.TextFormatter{
+		DisableColors:   true,
+		TimestampFormat: "2006-01-02 15:04:05.0000",
+	})
+}
+
+// list - prints up to n entries after the given ID
+func list(q *hsl.DeadLetterQueue, args []string) error {
+
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	n := fs.Int64("n", 20, "Max entries to list")
+	after := fs.String("after", "", "List entries after this ID, e.g. the last ID of the previous page")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	letters, err := q.List(ctx, *after, *n)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range letters {
+		fmt.Printf(
+			"%s\t%s\n  topic:   %s\n  error:   %s\n  payload: %s\n\n",
+			d.ID, d.Received.UTC().Format("2006-01-02T15:04:05.000Z"), d.Topic, d.Error, d.Payload,
+		)
+	}
+
+	return nil
+}
+
+// countErrors - prints the count of entries by error type, most common first
+func countErrors(q *hsl.DeadLetterQueue, args []string) error {
+
+	counts, err := q.ErrorTypes(ctx)
+	if err != nil {
+		return err
+	}
+
+	types := make([]string, 0, len(counts))
+	for t := range counts {
+		types = append(types, t)
+	}
+
+	sort.Slice(types, func(i, j int) bool { return counts[types[i]] > counts[types[j]] })
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "COUNT\tERROR TYPE")
+	for _, t := range types {
+		fmt.Fprintf(w, "%d\t%s\n", counts[t], t)
+	}
+
+	return w.Flush()
+}
+
+// redrive - decodes entries again and writes them to the sinks, e.g. once the
+// decoder has been fixed to accept numeric routes
+func redrive(q *hsl.DeadLetterQueue, cfg *hsl.Config, args []string) error {
+
+	fs := flag.NewFlagSet("redrive", flag.ContinueOnError)
+	errType := fs.String("type", "", "Only re-drive entries w. this error type (see `deadletter errors`)")
+	sinkList := fs.String("sinks", "redis", "Comma separated list of sinks to write to, e.g. redis,stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sinks, err := hsl.OpenSinks(ctx, cfg, *sinkList)
+	if err != nil {
+		return err
+	}
+	defer hsl.CloseSinks(sinks)
+
+	// Run the same checks as `cmd/mqtt`, re-driven entries would otherwise
+	// reach the sinks w.o. coordinates or from outside the service area
+	validator, err := hsl.NewValidatorFromConfig(cfg.Validation)
+	if err != nil {
+		return err
+	}
+
+	redriven, rejected, failed, err := q.Redrive(ctx, sinks, validator, *errType)
+
+	fmt.Printf("Re-drove %d entries, %d rejected by validation, %d still fail to decode\n", redriven, rejected, failed)
+	return err
+}
+
+// Lists, summarises, and re-drives the messages `cmd/mqtt` failed to decode,
+// see `hsl.DeadLetterQueue`
+func main() {
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg, err := hsl.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var run func(q *hsl.DeadLetterQueue, args []string) error
+
+	switch os.Args[1] {
+	case "list":
+		run = list
+	case "errors":
+		run = countErrors
+	case "redrive":
+		run = func(q *hsl.DeadLetterQueue, args []string) error { return redrive(q, cfg, args) }
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// NOTE: Close before exiting, log.Fatal && os.Exit skip deferred calls
+	q := hsl.NewDeadLetterQueue(hsl.InitRedisClient(ctx, cfg.Redis))
+
+	err = run(q, os.Args[2:])
+	if cerr := q.Close(); err == nil {
+		err = cerr
+	}
+
+	switch {
+	case err == flag.ErrHelp:
+		os.Exit(2)
+	case err != nil:
+		log.Fatal(err)
+	}
+}
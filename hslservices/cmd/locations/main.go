@@ -4,13 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	hsl "github.com/dmw2151/hsldatabridge"
 	"github.com/go-redis/redis/v8"
@@ -24,16 +28,6 @@ import (
 var (
 	ctx = context.Background()
 
-	// NOTE: Replace this with a Real Number of N Max Connections (currently static @ 100)
-	apiHandler = LocationsAPIHandler{
-		client:      hsl.InitRedisClient(ctx),
-		conns:       make([]*upgradedLocationListener, 100),
-		sem:         semaphore.NewWeighted(100),
-		mu:          sync.Mutex{},
-		openIdx:     0,
-		unregisterC: make(chan int),
-	}
-
 	// Upgrader for WS connections...
 	upgrader = websocket.Upgrader{
 		ReadBufferSize:  1024,
@@ -52,10 +46,91 @@ type LocationsAPIHandler struct {
 	unregisterC chan int
 }
 
+// newLocationsAPIHandler - creates a handler allowing up to maxConns live
+// locations subscribers
+func newLocationsAPIHandler(client *redis.Client, maxConns int) *LocationsAPIHandler {
+	return &LocationsAPIHandler{
+		client:      client,
+		conns:       make([]*upgradedLocationListener, maxConns),
+		sem:         semaphore.NewWeighted(int64(maxConns)),
+		mu:          sync.Mutex{},
+		openIdx:     0,
+		unregisterC: make(chan int),
+	}
+}
+
 // upgradedLocationListener to avoid any blocking on message fanout to client
 type upgradedLocationListener struct {
 	c  *websocket.Conn
 	cH chan []byte
+
+	// filter - the client's latest subscription (a *locationFilter), nil until the
+	// client subscribes, i.e. every event is sent
+	filter atomic.Value
+}
+
+// subscribeMsg - Sent by a client to set (or replace) the events sent on its
+// connection, e.g. when the map is panned. Empty fields match every event, e.g.
+//
+//	{"type": "subscribe", "bbox": [60.15, 24.90, 60.20, 24.98], "modes": ["tram"]}
+type subscribeMsg struct {
+	Type   string    `json:"type"`   // Always `subscribe`
+	BBox   []float64 `json:"bbox"`   // Min lat, min lng, max lat, max lng
+	Routes []string  `json:"routes"` // Route IDs as sent on the topic, e.g. `2550`
+	Modes  []string  `json:"modes"`  // e.g. `bus`, `tram`, `metro`
+}
+
+// locationFilter - A client's subscription, see subscribeMsg
+type locationFilter struct {
+	bbox   []float64
+	routes map[string]bool
+	modes  map[string]bool
+}
+
+// newLocationFilter - validates the subscription, returns the filter it describes
+func newLocationFilter(msg *subscribeMsg) (*locationFilter, error) {
+
+	if msg.Type != "subscribe" {
+		return nil, fmt.Errorf("unknown message type %q", msg.Type)
+	}
+
+	if len(msg.BBox) != 0 && (len(msg.BBox) != 4 || msg.BBox[0] > msg.BBox[2] || msg.BBox[1] > msg.BBox[3]) {
+		return nil, fmt.Errorf("bbox must be [min lat, min lng, max lat, max lng]")
+	}
+
+	set := func(values []string) map[string]bool {
+		m := make(map[string]bool, len(values))
+		for _, v := range values {
+			m[v] = true
+		}
+		return m
+	}
+
+	return &locationFilter{bbox: msg.BBox, routes: set(msg.Routes), modes: set(msg.Modes)}, nil
+}
+
+// match - reports whether the event is w.in the bbox, and on one of the routes
+// and modes, of the filter
+func (f *locationFilter) match(e *hsl.EventHolder) bool {
+
+	core := e.Event()
+	if core == nil {
+		return false
+	}
+
+	if len(f.bbox) == 4 && (core.Lat < f.bbox[0] || core.Lat > f.bbox[2] || core.Lng < f.bbox[1] || core.Lng > f.bbox[3]) {
+		return false
+	}
+
+	if len(f.routes) > 0 && !f.routes[core.RouteID] {
+		return false
+	}
+
+	if len(f.modes) > 0 && (e.Topic == nil || !f.modes[e.Topic.TransportMode]) {
+		return false
+	}
+
+	return true
 }
 
 // Healthcheck - Nothing More...
@@ -86,6 +161,8 @@ func (lh *LocationsAPIHandler) httpConnectionUpgrade(conn *websocket.Conn) {
 
 		lh.mu.Unlock()
 
+		go ull.read()
+
 		if err := ull.recv(lh.openIdx, lh.unregisterC); err != nil {
 			lh.sem.Release(1)
 			conn.Close()
@@ -117,11 +194,50 @@ func (ull *upgradedLocationListener) recv(idx int, callbackCh chan int) error {
 	return nil
 }
 
+// read - reads subscribe messages from the client until the connection is closed,
+// each replaces the connection's filter; invalid messages are answered w. an error
+// and leave the filter as it was
+func (ull *upgradedLocationListener) read() {
+
+	ull.c.SetReadLimit(4096)
+
+	for {
+		_, b, err := ull.c.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg subscribeMsg
+		err = json.Unmarshal(b, &msg)
+
+		var f *locationFilter
+		if err == nil {
+			f, err = newLocationFilter(&msg)
+		}
+
+		if err != nil {
+			// Written by recv, the connection allows one writer at a time
+			b, _ := json.Marshal(map[string]string{"error": err.Error()})
+			select {
+			case ull.cH <- b:
+			default:
+			}
+			continue
+		}
+
+		ull.filter.Store(f)
+		log.WithFields(
+			log.Fields{"BBox": msg.BBox, "Routes": msg.Routes, "Modes": msg.Modes},
+		).Debug("Updated Subscription")
+	}
+}
+
 // subscriptionFanout - subscribe to a topic (Redis PUB/SUB) channel and receive
 // messages for perpetuity.
 //
 // For each connection registered on the LocationsAPIHandler, push the message
-// along to that connection as well
+// along to that connection as well, if it matches the connection's filter. Each
+// message is decoded (at most) once, only if any connection has a filter
 func (lh *LocationsAPIHandler) subscriptionFanout() {
 
 	sub := lh.client.Subscribe(
@@ -141,8 +257,28 @@ func (lh *LocationsAPIHandler) subscriptionFanout() {
 		// cast msg -> msgB and then send to all listening connections...
 		msgB := []byte(msg.Payload)
 
+		var (
+			e       *hsl.EventHolder
+			decoded bool
+		)
+
 		for i, sub := range lh.conns {
 			if sub != nil {
+
+				if f, ok := sub.filter.Load().(*locationFilter); ok {
+					if !decoded {
+						decoded = true
+						if err := json.Unmarshal(msgB, &e); err != nil {
+							log.Errorf("Failed to Decode Event: %+v", err)
+							e = nil
+						}
+					}
+
+					if e == nil || !f.match(e) {
+						continue
+					}
+				}
+
 				// Never Block!!
 				select {
 				case sub.cH <- msgB:
@@ -156,6 +292,23 @@ func (lh *LocationsAPIHandler) subscriptionFanout() {
 	log.Info("Exit from PUB/SUB Channel")
 }
 
+// closeConnections - sends a close frame to each live locations subscriber
+// and closes the connection, ends each `recv` loop
+func (lh *LocationsAPIHandler) closeConnections() {
+
+	lh.mu.Lock()
+	defer lh.mu.Unlock()
+
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+
+	for _, ull := range lh.conns {
+		if ull != nil {
+			ull.c.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+			ull.c.Close()
+		}
+	}
+}
+
 func (lh *LocationsAPIHandler) unregisterConnections() {
 
 	for i := range lh.unregisterC {
@@ -200,9 +353,10 @@ func (lh *LocationsAPIHandler) historicallocationsHandler(w http.ResponseWriter,
 
 	journeyID := e.GetEventHash()
 
-	// TS.MRANGE uses a 5x nested structure for anything, wooof
+	// TS.MRANGE uses a 5x nested structure for anything, wooof; only the LAST
+	// compactions of the journey's series, see `hsl.DefaultSeries`
 	result, err := lh.client.Do(
-		lh.client.Context(), "TS.MRANGE", "-", "+", "FILTER", fmt.Sprintf("journey=%s", journeyID),
+		lh.client.Context(), "TS.MRANGE", "-", "+", "FILTER", fmt.Sprintf("journey=%s", journeyID), "agg=LAST",
 	).Result()
 
 	if err != nil {
@@ -226,9 +380,9 @@ func (lh *LocationsAPIHandler) historicallocationsHandler(w http.ResponseWriter,
 		series, positions := keys[0].(string), keys[2]
 
 		positionsArr := positions.([]interface{})
-		realLen = len(positionsArr)
 
 		if strings.HasSuffix(series, "gh:agg") {
+			realLen = len(positionsArr)
 			for i, tup := range positionsArr {
 				ts, gh := tup.([]interface{})[0].(int64), tup.([]interface{})[1].(string)
 				ghI, err := strconv.ParseFloat(gh, 64)
@@ -267,9 +421,123 @@ func (lh *LocationsAPIHandler) historicallocationsHandler(w http.ResponseWriter,
 	w.Write(b)
 }
 
+// rollupsHandler - returns network-wide rollups of a series by route, mode or
+// operator, e.g. the mean delay on route 550 over the last hour, in 5 minute buckets
+//
+// GET /rollups/?dim=route&value=550&series=delay&since=1h&bucket=5m
+//
+// `value` is optional, w.o. it every route (mode, operator) is returned
+func (lh *LocationsAPIHandler) rollupsHandler(w http.ResponseWriter, r *http.Request) {
+
+	q := r.URL.Query()
+
+	dim, series := q.Get("dim"), q.Get("series")
+	if _, ok := hsl.RollupDims[dim]; !ok {
+		http.Error(w, fmt.Sprintf("unknown dim %q, want route, mode or oper", dim), http.StatusBadRequest)
+		return
+	}
+
+	if series == "" {
+		series = "delay"
+	}
+
+	duration := func(key string, def time.Duration) (time.Duration, error) {
+		if v := q.Get(key); v != "" {
+			return time.ParseDuration(v)
+		}
+		return def, nil
+	}
+
+	since, err := duration("since", time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bucket, err := duration("bucket", time.Minute)
+	if err != nil || bucket < time.Second {
+		http.Error(w, "bucket must be a duration of at least 1s", http.StatusBadRequest)
+		return
+	}
+
+	rollups, err := hsl.QueryRollups(r.Context(), lh.client, dim, q.Get("value"), series, time.Now().Add(-since), bucket)
+	if err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+
+	b, _ := json.Marshal(rollups)
+	w.Write(b)
+}
+
+// nearbyVehiclesHandler - returns the live state of the vehicles near a point, nearest
+// first, e.g. the buses on routes 550 && 551 w.in 500m
+//
+// GET /vehicles/near?lat=60.17&lng=24.94&radius=500&mode=bus&route=2550,2551
+//
+// `radius` is in meters (default 500, max 10000); `mode` and `route` are optional,
+// comma separated lists; `limit` caps the vehicles returned (default 50)
+func (lh *LocationsAPIHandler) nearbyVehiclesHandler(w http.ResponseWriter, r *http.Request) {
+
+	q := r.URL.Query()
+
+	number := func(key string, def float64) (float64, error) {
+		if v := q.Get(key); v != "" {
+			return strconv.ParseFloat(v, 64)
+		}
+		return def, nil
+	}
+
+	list := func(key string) []string {
+		if v := q.Get(key); v != "" {
+			return strings.Split(v, ",")
+		}
+		return nil
+	}
+
+	lat, latErr := number("lat", 0)
+	lng, lngErr := number("lng", 0)
+	if q.Get("lat") == "" || q.Get("lng") == "" || latErr != nil || lngErr != nil {
+		http.Error(w, "lat and lng are required", http.StatusBadRequest)
+		return
+	}
+
+	radius, err := number("radius", 500)
+	if err != nil || radius <= 0 || radius > 10000 {
+		http.Error(w, "radius must be on (0, 10000] meters", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := number("limit", 50)
+	if err != nil || limit < 1 {
+		http.Error(w, "limit must be at least 1", http.StatusBadRequest)
+		return
+	}
+
+	vehicles, err := hsl.NearbyVehicles(r.Context(), lh.client, hsl.NearbyQuery{
+		Lat: lat, Lng: lng, Radius: radius, Modes: list("mode"), Routes: list("route"), Limit: int(limit),
+	})
+
+	if err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+
+	b, _ := json.Marshal(vehicles)
+	w.Write(b)
+}
+
 func init() {
 
-	// Set Logging Config
+	// Set Logging Config, see `locations.log_level`
 	log.SetOutput(os.Stdout)
 	log.SetLevel(log.DebugLevel)
 
@@ -277,15 +545,26 @@ func init() {
 		DisableColors:   true,
 		TimestampFormat: "2006-01-02 15:04:05.0000",
 	})
+}
+
+func main() {
+
+	quitChannel := make(chan os.Signal, 1)
+	signal.Notify(quitChannel, syscall.SIGINT, syscall.SIGTERM)
+
+	cfg, err := hsl.LoadConfig(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hsl.SetLogLevel(cfg.Locations.LogLevel)
 
 	// Initialize the LocationsAPI Handler & Have It Subscribe
 	// to Target Topics
+	apiHandler := newLocationsAPIHandler(hsl.InitRedisClient(ctx, cfg.Redis), cfg.Locations.MaxConns)
 
 	go apiHandler.subscriptionFanout()
 	go apiHandler.unregisterConnections()
-}
-
-func main() {
 
 	router := mux.NewRouter().StrictSlash(true)
 
@@ -298,7 +577,38 @@ func main() {
 	// Historical Locations Endpoint...
 	router.HandleFunc("/histlocations/", apiHandler.historicallocationsHandler)
 
-	log.Fatal(
-		http.ListenAndServe(":2152", router),
-	)
+	// Route, Mode && Operator Rollups Endpoint...
+	router.HandleFunc("/rollups/", apiHandler.rollupsHandler)
+
+	// Nearby Vehicles Endpoint, see `hsl.VehicleStore`...
+	router.HandleFunc("/vehicles/near", apiHandler.nearbyVehiclesHandler).Methods(http.MethodGet)
+
+	srv := &http.Server{Addr: cfg.Locations.Addr, Handler: router}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	<-quitChannel
+	log.Info("Shutting Down")
+
+	// Stop accepting requests, wait for in-flight requests to finish; websocket
+	// connections are hijacked and aren't tracked by the server, close them here
+	shutdownCtx, cancel := context.WithTimeout(ctx, cfg.Locations.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Errorf("Failed to Shutdown Server: %+v", err)
+	}
+
+	apiHandler.closeConnections()
+
+	// Closing the client closes the PUB/SUB channel, ends subscriptionFanout
+	if err := apiHandler.client.Close(); err != nil {
+		log.Errorf("Failed to Close Redis Client: %+v", err)
+	}
+
+	log.Info("Shutdown Complete")
 }
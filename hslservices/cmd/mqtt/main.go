@@ -2,219 +2,115 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"net"
+	"flag"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	hsl "github.com/dmw2151/hsldatabridge"
-	redis "github.com/go-redis/redis/v8"
-	"github.com/mmcloughlin/geohash"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 )
 
-var (
-	msgBroker   = hsl.NewMsgBroker(1024)
-	ctx, cancel = context.WithCancel(context.Background())
-	_           = hsl.InitMQTTClient(msgBroker)
-	redisClient = hsl.InitRedisClient(ctx)
-	nWorkers    = 10 // Set Variable for System CPU cap...
-)
-
-// statJourneyID checks if a journeyID already exists in the set of previously
-// seen JourneyID; attempts to SADD. Returns True if journey exists....
-func statJourneyID(client *redis.Client, key string, journeyID string) bool {
+var ctx, cancel = context.WithCancel(context.Background())
 
-	resp, err := client.Do(
-		ctx, "SADD", key, journeyID,
-	).Result()
+// serveMetrics - serves Prometheus metrics on addr at `/metrics`, returns the
+// server s.t. it can be shut down
+func serveMetrics(addr string, mb *hsl.MsgBroker, v *hsl.Validator) *http.Server {
 
-	if err != nil {
-		return false
+	if err := hsl.RegisterMetrics(prometheus.DefaultRegisterer, mb, v); err != nil {
+		log.Panic(err)
 	}
 
-	// If resp == 0; then already exists...
-	return resp.(int64) == 0
-}
-
-// createTimeSeriesPair - create a timeseries of events and maps it to
-// auto-update a secondary time series with a compaction rule...
-//
-// WARNING: by default this setup ONLY allows for mapping 1:1 src to target
-// event timeseries, should consider using something better to customize rules
-func createTimeSeriesPair(client *redis.Client, journeyID string, label string) {
-
-	// Initialize Creation Pipeline For a Statistic
-	pipe := client.TxPipeline()
-
-	// Create Parent && Child Series
-	pipe.Do(
-		ctx, "TS.CREATE", fmt.Sprintf("positions:%s:%s", journeyID, label),
-	)
-
-	pipe.Do(
-		ctx, "TS.CREATE", fmt.Sprintf("positions:%s:%s:agg", journeyID, label),
-		"RETENTION", 120*60*1000, "LABELS", label, 1, "journey", journeyID,
-	)
+	router := http.NewServeMux()
+	router.Handle("/metrics", promhttp.Handler())
 
-	_, err := pipe.Exec(ctx)
+	srv := &http.Server{Addr: addr, Handler: router}
 
-	if err != nil {
-		log.WithFields(
-			log.Fields{
-				"JourneyID":   journeyID,
-				"Series":      fmt.Sprintf("positions:%s:%s", journeyID, label),
-				"ChildSeries": fmt.Sprintf("positions:%s:%s:agg", journeyID, label),
-			},
-		).Warn("Create TimeSeries Root Series Failed: ", err)
-	}
-
-	// Using a second pipe, create a rule, split into 2 stages to ensure parent && child
-	// series exist first....
-	pipe.Do(
-		ctx, "TS.CREATERULE",
-		fmt.Sprintf("positions:%s:%s", journeyID, label),
-		fmt.Sprintf("positions:%s:%s:agg", journeyID, label),
-		"AGGREGATION", "LAST", 15000,
-	)
-
-	_, err = pipe.Exec(ctx)
+	go func() {
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			log.Error(err)
+		}
+	}()
 
-	if err != nil {
-		log.WithFields(
-			log.Fields{
-				"JourneyID":   journeyID,
-				"Series":      fmt.Sprintf("positions:%s:%s", journeyID, label),
-				"ChildSeries": fmt.Sprintf("positions:%s:%s:agg", journeyID, label),
-			},
-		).Warn("Create TimeSeries Pair Failed: ", err)
-	}
+	return srv
 }
 
-// Launch some workers here...
-func writeRedis(ctx context.Context, C <-chan []byte, client *redis.Client) {
-
-	for msg := range C {
-
-		// Receive the content of the MQTT message and de-serialize bytes into
-		// struct
-		e := &hsl.EventHolder{}
-		err := hsl.DeserializeMQTTBody(msg, e)
-
-		if err != nil {
-			switch err := err.(type) {
-			case *hsl.MQTTValidationError:
-
-				// Most common error is Missing or Bad Coords; See defn for
-				// `hsl.MQTTValidationError` for more...
-				log.WithFields(log.Fields{"Body": e}).Debug("%+v", err)
-
-			default:
-				// The entry was not deserializable into a known msg types
-				// Most often an error from the source feed, e.g the feed published
-				// a route as 123 instead of "123", fail to unmarshal string into Go
-				log.WithFields(log.Fields{"Body": e}).Debug("%+v", err)
+// flushSinks - periodically flushes sinks that buffer writes, until ctx is cancelled
+func flushSinks(ctx context.Context, sinks []hsl.Sink, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, sink := range sinks {
+				if err := sink.Flush(ctx); err != nil {
+					log.Errorf("Failed to Flush Sink: %+v", err)
+				}
 			}
-
-			continue
 		}
+	}
+}
 
-		// Main procedure for adding a series keys, values to the redis
-		// instance
-		// MEMOIZE!!
-		journeyID := e.VP.GetEventHash()
-
-		// Check if JourneyID is known...
-		journeyExists := statJourneyID(client, "journeyID", journeyID)
+// logThroughput - logs the messages received on each topic filter over the
+// previous interval, e.g. to compare bus, tram, and metro volumes, any
+// messages dropped or spilled to disk, and events rejected by each rule
+func logThroughput(mb *hsl.MsgBroker, v *hsl.Validator, interval time.Duration) {
 
-		// if not...then create the timeseries pair for the journey...
-		if !(journeyExists) {
+	prev := mb.Received()
+	prevDropped, prevSpilled := mb.Dropped(), mb.Spilled()
+	prevRejected := v.Rejected()
 
+	for range time.Tick(interval) {
+		cur := mb.Received()
+		for filter, n := range cur {
 			log.WithFields(
-				log.Fields{
-					"JourneyID": journeyID,
-				},
-			).Info("New Journey Registered")
-
-			createTimeSeriesPair(client, journeyID, "speed")
-			createTimeSeriesPair(client, journeyID, "gh")
+				log.Fields{"Topic": filter, "Msgs": n - prev[filter], "Interval": interval},
+			).Info("Topic Throughput")
 		}
-
-		// Write The incoming event to multiple locations using
-		// a single client Tx pipeline, cuts back on some network
-		// round-trip
-		pipe := client.TxPipeline()
-
-		// 1. Publish full body...
-		pipe.Publish(
-			ctx, "currentLocationsPS", msg,
-		)
-
-		// 2. XADD the full event body to a stream of events, these
-		// are swept up by a gears function and written behind to a DB
-		// every XXXXms
-		pipe.XAdd(
-			ctx, &redis.XAddArgs{
-				Stream: "events",
-				Values: []interface{}{
-					"rt", e.VP.RouteID,
-					"jid", journeyID,
-					"lat", e.VP.Lat,
-					"lng", e.VP.Lng,
-					"time", e.VP.Timestamp,
-					"spd", e.VP.Spd,
-					"acc", e.VP.Acc,
-					"dl", e.VP.DeltaToSchedule,
-				},
-			},
-		)
-
-		// 3. TS.ADD a series of statistics to the timeseries created
-		// by `createTimeSeriesPair`
-		pipe.Do(
-			ctx,
-			"TS.ADD", fmt.Sprintf("positions:%s:speed", journeyID),
-			"*",
-			e.VP.Spd,
-			"RETENTION", 60*1000,
-			"CHUNK_SIZE", 16,
-			"ON_DUPLICATE", "LAST",
-		)
-
-		pipe.Do(
-			ctx,
-			"TS.ADD", fmt.Sprintf("positions:%s:gh", journeyID),
-			"*",
-			geohash.EncodeIntWithPrecision(e.VP.Lat, e.VP.Lng, 64),
-			"RETENTION", 60*1000,
-			"ON_DUPLICATE", "LAST",
-		)
-
-		// Execute Pipe!
-		_, err = pipe.Exec(ctx)
-
-		// Failed to Write an Event
-		if err != nil {
-
-			if err, ok := err.(net.Error); ok {
-				log.Errorf("Redis Down: %+v", err)
+		prev = cur
+
+		// Workers falling behind; StagingC was full for some messages
+		dropped, spilled := mb.Dropped(), mb.Spilled()
+		if dropped > prevDropped || spilled > prevSpilled {
+			log.WithFields(log.Fields{
+				"Dropped":  dropped - prevDropped,
+				"Spilled":  spilled - prevSpilled,
+				"Interval": interval,
+			}).Warn("Staging Channel Overflow")
+		}
+		prevDropped, prevSpilled = dropped, spilled
+
+		rejected := v.Rejected()
+		for rule, n := range rejected {
+			if n > prevRejected[rule] {
+				log.WithFields(
+					log.Fields{"Rule": rule, "Events": n - prevRejected[rule], "Interval": interval},
+				).Info("Events Rejected")
 			}
+		}
+		prevRejected = rejected
+	}
+}
 
-			log.WithFields(
-				log.Fields{
-					"Body": fmt.Sprintf("positions:%s:*", journeyID),
-				},
-			).Errorf("Failed to Write Event: %+v", err)
-
-		} else {
-
-			log.WithFields(
-				log.Fields{"Journey": journeyID},
-			).Debug("Wrote Event")
-
+// usesSink - reports whether name is in a comma separated list of sinks, see
+// hsl.OpenSinks
+func usesSink(names, name string) bool {
+	for _, n := range strings.Split(names, ",") {
+		if strings.TrimSpace(n) == name {
+			return true
 		}
 	}
+	return false
 }
 
 func init() {
@@ -227,20 +123,129 @@ func init() {
 	// Can be any io.Writer, see below for File example
 	log.SetOutput(os.Stdout)
 
-	// Only log the warning severity or above.
+	// Only log the warning severity or above, see `mqtt.log_level`
 	log.SetLevel(log.WarnLevel)
 }
 
 func main() {
 
-	quitChannel := make(chan os.Signal, 1)
+	var (
+		quitChannel = make(chan os.Signal, 1)
+		workers     sync.WaitGroup
+		flusher     sync.WaitGroup
+	)
+
+	signal.Notify(quitChannel, syscall.SIGINT, syscall.SIGTERM)
 
-	// Start Staging Channel -> Redis Workers
-	for i := 0; i < nWorkers; i++ {
-		go writeRedis(ctx, msgBroker.StagingC, redisClient)
+	cfg, err := hsl.LoadConfig(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	signal.Notify(quitChannel, syscall.SIGINT, syscall.SIGTERM)
+	hsl.SetLogLevel(cfg.MQTT.LogLevel)
+
+	sinks, err := hsl.OpenSinks(ctx, cfg, cfg.MQTT.Sinks)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	validator, err := hsl.NewValidatorFromConfig(cfg.Validation)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	msgBroker := hsl.NewMsgBroker(cfg.MQTT.StagingSize)
+
+	// The dead-letter queue is kept in Redis, only w. the redis sink s.t. e.g. a
+	// stdout-only run doesn't need Redis; RunWorker only logs failures w.o. one
+	var deadLetters *hsl.DeadLetterQueue
+	if usesSink(cfg.MQTT.Sinks, "redis") {
+		deadLetters = hsl.NewDeadLetterQueue(hsl.InitRedisClient(ctx, cfg.Redis))
+	}
+
+	// Start Staging Channel -> Shard -> Sink Workers, one worker per shard s.t.
+	// each vehicle's events are written in order
+	shards := hsl.NewShards(cfg.MQTT.Workers, cfg.MQTT.BatchSize)
+	msgBroker.Shards = shards
+	go hsl.ShardByVehicle(msgBroker.StagingC, shards)
+
+	for _, shard := range shards {
+		workers.Add(1)
+		go func(shard <-chan *hsl.StagedMessage) {
+			defer workers.Done()
+			hsl.RunWorker(ctx, shard, sinks, validator, deadLetters, cfg.MQTT.BatchSize, cfg.MQTT.BatchInterval)
+		}(shard)
+	}
+
+	// Connect once the workers are ready, messages arrive as soon as the
+	// first subscription is granted
+	client := hsl.InitMQTTClient(cfg.MQTT, msgBroker)
+
+	metricsSrv := serveMetrics(cfg.MQTT.MetricsAddr, msgBroker, validator)
+
+	flushCtx, stopFlushing := context.WithCancel(ctx)
+	flusher.Add(1)
+	go func() {
+		defer flusher.Done()
+		flushSinks(flushCtx, sinks, time.Second)
+	}()
+
+	go logThroughput(msgBroker, validator, time.Minute)
+
 	<-quitChannel
+	log.Warn("Shutting Down")
+
+	// 1. && 2. Unsubscribe && disconnect MQTT, close StagingC
+	msgBroker.Close(*client, time.Second)
+
+	// 3. Let the workers write everything already staged; past the timeout, cancel
+	// their writes && wait for them to return s.t. nothing is still writing once
+	// the sinks are closed
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(cfg.MQTT.ShutdownTimeout):
+		log.WithFields(
+			log.Fields{"Staged": msgBroker.Staged(), "Timeout": cfg.MQTT.ShutdownTimeout},
+		).Error("Workers Did Not Finish Before Timeout")
+
+		cancel()
+		<-done
+	}
+
+	stopFlushing()
+	flusher.Wait()
+	cancel()
+
+	// 4. Stop serving metrics
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+
+	if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+		log.Errorf("Failed to Shutdown Metrics Server: %+v", err)
+	}
+
+	// 5. Flush && close the sinks, and Redis
+	for _, sink := range sinks {
+		if err := sink.Flush(shutdownCtx); err != nil {
+			log.Errorf("Failed to Flush Sink: %+v", err)
+		}
+	}
+
+	if err := hsl.CloseSinks(sinks); err != nil {
+		log.Errorf("Failed to Close Sinks: %+v", err)
+	}
+
+	if deadLetters != nil {
+		if err := deadLetters.Close(); err != nil {
+			log.Errorf("Failed to Close Dead-Letter Queue: %+v", err)
+		}
+	}
 
+	log.Warn("Shutdown Complete")
 }
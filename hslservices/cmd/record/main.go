@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	hsl "github.com/dmw2151/hsldatabridge"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	archiveDir    = flag.String("dir", "./archive", "Directory to write archive files to")
+	archiveMaxMB  = flag.Int64("max-mb", 256, "Start a new file after N MB (uncompressed)")
+	archiveMaxAge = flag.Duration("max-age", time.Hour, "Start a new file after this duration")
+)
+
+func init() {
+	// Set Logging Config
+	log.SetOutput(os.Stdout)
+	log.SetLevel(log.InfoLevel)
+
+	log.SetFormatter(&log.TextFormatter{
+		DisableColors:   true,
+		TimestampFormat: "2006-01-02 15:04:05.0000",
+	})
+}
+
+// Records every message received on the topics in `mqtt.topics` to a rotating
+// archive, see `cmd/replay` to push an archive back through the pipeline
+func main() {
+
+	cfg, err := hsl.LoadConfig(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	msgBroker := hsl.NewMsgBroker(cfg.MQTT.StagingSize)
+
+	w, err := hsl.NewArchiveWriter(*archiveDir, *archiveMaxMB<<20, *archiveMaxAge)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// NOTE: Connect only once the writer is ready, messages are buffered on
+	// StagingC in the meantime
+	_ = hsl.InitMQTTClient(cfg.MQTT, msgBroker)
+
+	quitChannel := make(chan os.Signal, 1)
+	signal.Notify(quitChannel, syscall.SIGINT, syscall.SIGTERM)
+
+	var n int
+
+	for {
+		select {
+		case msg := <-msgBroker.StagingC:
+
+			if err := w.Write(msg); err != nil {
+				log.WithFields(log.Fields{"Topic": msg.Topic}).Errorf("Failed to Record Msg: %+v", err)
+				continue
+			}
+
+			msg.Ack()
+			n++
+
+		case <-quitChannel:
+			// Must close to flush the gzip footer, otherwise the last file is truncated
+			if err := w.Close(); err != nil {
+				log.Error(err)
+			}
+
+			log.WithFields(log.Fields{"Msgs": n}).Info("Recording Stopped")
+			return
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	hsl "github.com/dmw2151/hsldatabridge"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	msgBroker *hsl.MsgBroker
+	ctx       = context.Background()
+
+	speed = flag.Float64("speed", 1, "Replay speed relative to the recording, e.g. 1 (real-time), 10, or 0 for max speed")
+
+	// Deprecated: use -mqtt.workers && -mqtt.sinks, shared w. cmd/mqtt
+	nWorkers = flag.Int("workers", 0, "Deprecated, use -mqtt.workers")
+	sinkList = flag.String("sinks", "", "Deprecated, use -mqtt.sinks")
+)
+
+func init() {
+	// Set Logging Config
+	log.SetOutput(os.Stdout)
+	log.SetLevel(log.InfoLevel)
+
+	log.SetFormatter(&log.TextFormatter{
+		DisableColors:   true,
+		TimestampFormat: "2006-01-02 15:04:05.0000",
+	})
+}
+
+// replayArchive - pushes every message in an archive file to StagingC, pacing
+// messages by their receive times; returns the count of messages pushed
+func replayArchive(path string, clock func(time.Time)) (int, error) {
+
+	r, err := hsl.OpenArchive(path)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	var n int
+
+	for {
+		msg, err := r.Next()
+
+		if err == io.EOF {
+			return n, nil
+		}
+
+		if err != nil {
+			return n, err
+		}
+
+		clock(msg.Received)
+		msgBroker.StagingC <- msg
+		n++
+	}
+}
+
+// pacer - returns a func that blocks until a message received at `recv` is due,
+// relative to the first message seen and scaled by speed
+func pacer(speed float64) func(time.Time) {
+
+	var first, start time.Time
+
+	return func(recv time.Time) {
+
+		if speed <= 0 {
+			return
+		}
+
+		if first.IsZero() {
+			first, start = recv, time.Now()
+			return
+		}
+
+		due := start.Add(time.Duration(float64(recv.Sub(first)) / speed))
+		if wait := time.Until(due); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+}
+
+// Pushes the archives written by `cmd/record` through the same StagingC ->
+// RunWorker -> sinks path as `cmd/mqtt`, w. the same `mqtt.workers`, `mqtt.sinks`,
+// and `mqtt.staging_size`, e.g.
+//
+// replay -speed 0 ./archive/hfp-20210514T05*.ndjson.gz
+//
+// NOTE: Rollups are summed, replaying into a Redis that already holds the rollups
+// for an archive's time range counts those positions twice; pass `-timeseries.rollup_by=`
+// to leave rollups out
+func main() {
+
+	cfg, err := hsl.LoadConfig(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The deprecated flags, if set, override the config
+	if *nWorkers > 0 {
+		log.Warn("-workers is deprecated, use -mqtt.workers")
+		cfg.MQTT.Workers = *nWorkers
+	}
+
+	if *sinkList != "" {
+		log.Warn("-sinks is deprecated, use -mqtt.sinks")
+		cfg.MQTT.Sinks = *sinkList
+	}
+
+	msgBroker = hsl.NewMsgBroker(cfg.MQTT.StagingSize)
+
+	var paths []string
+	for _, pattern := range flag.Args() {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			log.Fatal(err)
+		}
+		paths = append(paths, matches...)
+	}
+
+	// Archive names sort by the time they were opened
+	sort.Strings(paths)
+
+	if len(paths) == 0 {
+		log.Fatal("No archive files to replay")
+	}
+
+	sinks, err := hsl.OpenSinks(ctx, cfg, cfg.MQTT.Sinks)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	validator, err := hsl.NewValidatorFromConfig(cfg.Validation)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Start Staging Channel -> Shard -> Sink Workers, as in cmd/mqtt
+	var wg sync.WaitGroup
+	shards := hsl.NewShards(cfg.MQTT.Workers, cfg.MQTT.BatchSize)
+	go hsl.ShardByVehicle(msgBroker.StagingC, shards)
+
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard <-chan *hsl.StagedMessage) {
+			defer wg.Done()
+			hsl.RunWorker(ctx, shard, sinks, validator, nil, cfg.MQTT.BatchSize, cfg.MQTT.BatchInterval)
+		}(shard)
+	}
+
+	var (
+		clock = pacer(*speed)
+		start = time.Now()
+		total int
+	)
+
+	for _, path := range paths {
+		n, err := replayArchive(path, clock)
+		total += n
+
+		if err != nil {
+			// NOTE: The last file of a recording may be truncated, keep going
+			log.WithFields(log.Fields{"Archive": path}).Errorf("Failed to Read Archive: %+v", err)
+		}
+
+		log.WithFields(log.Fields{"Archive": path, "Msgs": n}).Info("Replayed Archive")
+	}
+
+	// Wait for the workers to write everything that's been staged
+	close(msgBroker.StagingC)
+	wg.Wait()
+
+	if err := hsl.CloseSinks(sinks); err != nil {
+		log.Error(err)
+	}
+
+	elapsed := time.Since(start)
+
+	log.WithFields(log.Fields{
+		"Msgs":     total,
+		"Elapsed":  elapsed,
+		"MsgsPerS": float64(total) / elapsed.Seconds(),
+	}).Info("Replay Complete")
+
+	for rule, n := range validator.Rejected() {
+		log.WithFields(log.Fields{"Rule": rule, "Events": n}).Info("Events Rejected")
+	}
+}
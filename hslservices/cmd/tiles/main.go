@@ -1,16 +1,22 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
+	hsl "github.com/dmw2151/hsldatabridge"
 	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
 )
 
-var layerDir = os.Getenv("TILE_DIRECTORY")
+// layerDir - root of the tile layers, see `tiles.dir`
+var layerDir string
 
 func healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Write(
@@ -68,7 +74,7 @@ func getTile(w http.ResponseWriter, r *http.Request) {
 }
 
 func init() {
-	// Set Logging Config
+	// Set Logging Config, see `tiles.log_level`
 	log.SetOutput(os.Stdout)
 	log.SetLevel(log.DebugLevel)
 
@@ -84,6 +90,17 @@ func init() {
 
 func main() {
 
+	quitChannel := make(chan os.Signal, 1)
+	signal.Notify(quitChannel, syscall.SIGINT, syscall.SIGTERM)
+
+	cfg, err := hsl.LoadConfig(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hsl.SetLogLevel(cfg.Tiles.LogLevel)
+	layerDir = cfg.Tiles.Dir
+
 	router := mux.NewRouter().StrictSlash(true)
 
 	// Healthcheck the API...
@@ -92,7 +109,24 @@ func main() {
 	// Access the Tiles on Disk; Serve them to a Webpage
 	router.HandleFunc("/{layer}/{z}/{x}/{y}", getTile).Methods("GET")
 
-	log.Fatal(
-		http.ListenAndServe(":2151", router),
-	)
+	srv := &http.Server{Addr: cfg.Tiles.Addr, Handler: router}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	<-quitChannel
+	log.Info("Shutting Down")
+
+	// Stop accepting requests, wait for in-flight requests to finish
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Tiles.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Errorf("Failed to Shutdown Server: %+v", err)
+	}
+
+	log.Info("Shutdown Complete")
 }
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"strconv"
+	"time"
+
+	hsl "github.com/dmw2151/hsldatabridge"
+	redis "github.com/go-redis/redis/v8"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	eventsStream = "events"
+	groupName    = "writebehind"
+
+	// NOTE: Tune batch size and block duration for your system!!!
+	batchSize  = 10000
+	blockFor   = 5 * time.Second
+	retryAfter = 5 * time.Second
+	trimEvery  = 30 * time.Second
+)
+
+var ctx = context.Background()
+
+// eventColumns - columns of `statistics.events` written from each stream entry,
+// in the order returned by eventRow
+var eventColumns = []string{
+	"id", "event_time", "ev", "jid", "route_id", "mode", "headsign", "next_stop",
+	"lat", "lng", "spd", "acc", "dl", "stop_id", "ttarr", "ttdep", "drst",
+	"tlp_request_id", "tlp_request_type", "tlp_priority_level", "tlp_reason",
+	"tlp_att_seq", "tlp_decision", "junction_id", "signal_group_id", "dr_type",
+}
+
+// field readers - each returns nil (NULL) if the field is missing from the entry,
+// (i.e. not sent for that event type) or can't be parsed
+func str(v map[string]interface{}, key string) interface{} {
+	s, ok := v[key].(string)
+	if !ok {
+		return nil
+	}
+	return s
+}
+
+func float(v map[string]interface{}, key string) interface{} {
+	s, ok := v[key].(string)
+	if !ok {
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+func integer(v map[string]interface{}, key string) interface{} {
+	s, ok := v[key].(string)
+	if !ok {
+		return nil
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return i
+}
+
+func timestamp(v map[string]interface{}, key string) interface{} {
+	s, ok := v[key].(string)
+	if !ok {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	return t
+}
+
+// eventRow - maps a stream entry written by the redis sink to a row of
+// `statistics.events`, see eventColumns
+func eventRow(msg redis.XMessage) []interface{} {
+
+	v := msg.Values
+
+	// `time` is the vehicle's timestamp, UTC seconds
+	var eventTime interface{}
+	if tsi, ok := integer(v, "time").(int64); ok {
+		eventTime = time.Unix(tsi, 0).UTC()
+	}
+
+	// Previous versions of the mqtt broker didn't write `ev`, these are all positions
+	ev := str(v, "ev")
+	if ev == nil {
+		ev = string(hsl.EventTypeVP)
+	}
+
+	return []interface{}{
+		msg.ID, eventTime, ev, str(v, "jid"), str(v, "rt"), str(v, "mode"), str(v, "hdsg"), str(v, "nxt"),
+		float(v, "lat"), float(v, "lng"), float(v, "spd"), float(v, "acc"), float(v, "dl"),
+		integer(v, "stop"), timestamp(v, "ttarr"), timestamp(v, "ttdep"), integer(v, "drst"),
+		integer(v, "tlpreq"), str(v, "tlptype"), str(v, "tlpprio"), str(v, "tlpreason"),
+		integer(v, "tlpatt"), str(v, "tlpdec"), integer(v, "sid"), integer(v, "sgid"), integer(v, "drtype"),
+	}
+}
+
+// writeBatch - COPY a batch of entries to PostgreSQL in a single transaction.
+//
+// Entries are copied to a staging table first and then inserted, skipping any
+// IDs already written, s.t. an entry redelivered after a commit (but before the
+// XACK) isn't written twice
+func writeBatch(ctx context.Context, pool *pgxpool.Pool, msgs []redis.XMessage) error {
+
+	rows := make([][]interface{}, len(msgs))
+	for i, msg := range msgs {
+		rows[i] = eventRow(msg)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE events_stage (LIKE statistics.events INCLUDING DEFAULTS) ON COMMIT DROP
+	`); err != nil {
+		return err
+	}
+
+	if _, err := tx.CopyFrom(
+		ctx, pgx.Identifier{"events_stage"}, eventColumns, pgx.CopyFromRows(rows),
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO statistics.events SELECT * FROM events_stage ON CONFLICT (id) DO NOTHING
+	`); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func init() {
+	// Set Logging Config
+	log.SetOutput(os.Stdout)
+	log.SetLevel(log.InfoLevel)
+
+	log.SetFormatter(&log.TextFormatter{
+		DisableColors:   true,
+		TimestampFormat: "2006-01-02 15:04:05.0000",
+	})
+}
+
+// Reads the events stream through a consumer group and writes each batch to
+// `statistics.events`, replaces the RedisGears write-behind
+func main() {
+
+	cfg, err := hsl.LoadConfig(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	redisClient := hsl.InitRedisClient(ctx, cfg.Redis)
+	pgPool := hsl.InitPostgresClient(ctx, cfg.Postgres)
+
+	consumer, _ := os.Hostname()
+
+	sc := hsl.NewStreamConsumer(redisClient, eventsStream, groupName, consumer)
+	sc.BatchSize, sc.Block, sc.RetryAfter = batchSize, blockFor, retryAfter
+
+	// Remove entries once written by every group reading the stream (the Gears
+	// write-behind removed them once written, w. `trimStream`) to keep Redis memory flat
+	sc.TrimInterval = trimEvery
+
+	err = sc.Run(ctx, func(ctx context.Context, msgs []redis.XMessage) error {
+		if err := writeBatch(ctx, pgPool, msgs); err != nil {
+			return err
+		}
+		log.WithFields(log.Fields{"Entries": len(msgs)}).Debug("Wrote Batch")
+		return nil
+	})
+
+	if err != nil {
+		log.Panic(err)
+	}
+}
@@ -0,0 +1,491 @@
+package hsldatabridge
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Config - Settings shared by the mqtt, locations, and tiles commands (and the
+// tools built on the same packages). Loaded w. LoadConfig from, in increasing order
+// of precedence: defaults, an optional YAML file, env vars, and flags.
+//
+// Each setting is named by its YAML path, used as the flag name (e.g. `-mqtt.workers`),
+// and has an env var (e.g. `MQTT_N_WORKERS`), see the `env` tags
+type Config struct {
+	Redis      RedisConfig      `yaml:"redis"`
+	Postgres   PostgresConfig   `yaml:"postgres"`
+	MQTT       MQTTConfig       `yaml:"mqtt"`
+	Validation ValidationConfig `yaml:"validation"`
+	Journeys   JourneysConfig   `yaml:"journeys"`
+	Vehicles   VehiclesConfig   `yaml:"vehicles"`
+	TimeSeries TimeSeriesConfig `yaml:"timeseries"`
+	Locations  LocationsConfig  `yaml:"locations"`
+	Tiles      TilesConfig      `yaml:"tiles"`
+}
+
+// RedisConfig -
+type RedisConfig struct {
+	Host     string `yaml:"host" env:"REDIS_HOST"`
+	Port     int    `yaml:"port" env:"REDIS_PORT"`
+	DB       int    `yaml:"db" env:"REDIS_DB"`
+	Password string `yaml:"password" env:"REDISCLI_AUTH" secret:"true"`
+
+	// JourneyCacheSize, JourneyCacheTTL - journeys known to the redis sink, see JourneyCache
+	JourneyCacheSize int           `yaml:"journey_cache_size" env:"REDIS_JOURNEY_CACHE_SIZE"`
+	JourneyCacheTTL  time.Duration `yaml:"journey_cache_ttl" env:"REDIS_JOURNEY_CACHE_TTL"`
+
+	// Pipeline - "tx" wraps each batch in MULTI/EXEC, "plain" sends it as a
+	// plain pipeline, i.e. other clients may see part of a batch
+	Pipeline string `yaml:"pipeline" env:"REDIS_PIPELINE"`
+}
+
+// PostgresConfig - NOTE: The password is read from PGPASSWORD by pgx, same as psql
+type PostgresConfig struct {
+	Host string `yaml:"host" env:"POSTGRES_HOST"`
+	DB   string `yaml:"db" env:"POSTGRES_DB"`
+	User string `yaml:"user" env:"POSTGRES_USER"`
+}
+
+// MQTTConfig - Settings for the MQTT connector (and cmd/record)
+type MQTTConfig struct {
+	Broker string `yaml:"broker" env:"MQTT_BROKER"`
+	Port   int    `yaml:"port" env:"MQTT_PORT"`
+
+	// Topics - comma separated `filter=qos` pairs, see ParseSubscriptions; Topic is
+	// kept for a single filter at QoS 1 and used only if Topics is empty
+	Topics string `yaml:"topics" env:"MQTT_TOPICS"`
+	Topic  string `yaml:"topic" env:"MQTT_TOPIC"`
+
+	Workers     int    `yaml:"workers" env:"MQTT_N_WORKERS"`
+	StagingSize int    `yaml:"staging_size" env:"MQTT_STAGING_SIZE"`
+	Sinks       string `yaml:"sinks" env:"MQTT_SINKS"`
+
+	// BatchSize, BatchInterval - each worker writes up to BatchSize events per call
+	// to the sinks, or whatever it has BatchInterval after the first event of a batch
+	BatchSize     int           `yaml:"batch_size" env:"MQTT_BATCH_SIZE"`
+	BatchInterval time.Duration `yaml:"batch_interval" env:"MQTT_BATCH_INTERVAL"`
+
+	// Overflow - "drop" or "spill", see `SpillQueue`
+	Overflow   string `yaml:"overflow" env:"MQTT_OVERFLOW"`
+	SpillDir   string `yaml:"spill_dir" env:"MQTT_SPILL_DIR"`
+	SpillMaxMB int    `yaml:"spill_max_mb" env:"MQTT_SPILL_MAX_MB"`
+
+	// Delivery - "at-most-once" or "at-least-once"; at-least-once requires
+	// a fixed client ID s.t. the broker can resume the session after a restart
+	Delivery string `yaml:"delivery" env:"MQTT_DELIVERY"`
+	ClientID string `yaml:"client_id" env:"MQTT_CLIENT_ID"`
+
+	MetricsAddr     string        `yaml:"metrics_addr" env:"METRICS_ADDR"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"MQTT_SHUTDOWN_TIMEOUT"`
+	LogLevel        string        `yaml:"log_level" env:"MQTT_LOG_LEVEL"`
+}
+
+// ValidationConfig - Limits for the validation rules, see validation.go
+type ValidationConfig struct {
+	Rules  string        `yaml:"rules" env:"VALIDATION_RULES"` // Comma separated rule codes
+	BBox   string        `yaml:"bbox" env:"VALIDATION_BBOX"`   // minLat,minLng,maxLat,maxLng
+	MaxSpd float64       `yaml:"max_spd" env:"VALIDATION_MAX_SPD"`
+	MaxAcc float64       `yaml:"max_acc" env:"VALIDATION_MAX_ACC"`
+	Skew   time.Duration `yaml:"skew" env:"VALIDATION_SKEW"`
+
+	// Required - fields required for each event type, see ParseRequiredFields
+	Required   string `yaml:"required" env:"VALIDATION_REQUIRED"`
+	HeadingMin int    `yaml:"heading_min" env:"VALIDATION_HEADING_MIN"`
+	HeadingMax int    `yaml:"heading_max" env:"VALIDATION_HEADING_MAX"`
+}
+
+// JourneysConfig - Timeouts for the journey lifecycle, see JourneyTracker
+type JourneysConfig struct {
+	IdleAfter    time.Duration `yaml:"idle_after" env:"JOURNEYS_IDLE_AFTER"`
+	FinishAfter  time.Duration `yaml:"finish_after" env:"JOURNEYS_FINISH_AFTER"`
+	ReapInterval time.Duration `yaml:"reap_interval" env:"JOURNEYS_REAP_INTERVAL"`
+}
+
+// VehiclesConfig - The live vehicle state, see VehicleStore; stale vehicles are
+// evicted every journeys.reap_interval
+type VehiclesConfig struct {
+	IdleAfter time.Duration `yaml:"idle_after" env:"VEHICLES_IDLE_AFTER"`
+}
+
+// TimeSeriesConfig - The timeseries created for each journey, see SeriesDef. Only
+// set from YAML, e.g.
+//
+//	timeseries:
+//	  series:
+//	    - name: occupancy
+//	      field: occu
+//	      retention: 5m
+//	      rules:
+//	        - {aggregation: MAX, bucket: 1m, retention: 2h}
+//
+// Series w. `rollup: true` are also summed (w. a count) by each dimension in
+// RollupBy, see RollupDims, into buckets of RollupBucket.
+//
+// Samples are written at the vehicle's timestamp; positions more than Lateness behind
+// the latest position of their journey are left out of the journey's series
+type TimeSeriesConfig struct {
+	Series   []SeriesDef   `yaml:"series"`
+	Lateness time.Duration `yaml:"lateness" env:"TIMESERIES_LATENESS"`
+
+	RollupBy        string        `yaml:"rollup_by" env:"TIMESERIES_ROLLUP_BY"` // Comma separated, e.g. route,mode,oper
+	RollupBucket    time.Duration `yaml:"rollup_bucket" env:"TIMESERIES_ROLLUP_BUCKET"`
+	RollupRetention time.Duration `yaml:"rollup_retention" env:"TIMESERIES_ROLLUP_RETENTION"`
+}
+
+// LocationsConfig -
+type LocationsConfig struct {
+	Addr            string        `yaml:"addr" env:"LOCATIONS_ADDR"`
+	MaxConns        int           `yaml:"max_conns" env:"LOCATIONS_MAX_CONNS"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"LOCATIONS_SHUTDOWN_TIMEOUT"`
+	LogLevel        string        `yaml:"log_level" env:"LOCATIONS_LOG_LEVEL"`
+}
+
+// TilesConfig -
+type TilesConfig struct {
+	Addr            string        `yaml:"addr" env:"TILES_ADDR"`
+	Dir             string        `yaml:"dir" env:"TILE_DIRECTORY"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"TILES_SHUTDOWN_TIMEOUT"`
+	LogLevel        string        `yaml:"log_level" env:"TILES_LOG_LEVEL"`
+}
+
+// DefaultConfig - returns the settings used when nothing else is set
+func DefaultConfig() *Config {
+	return &Config{
+		Redis: RedisConfig{
+			Host:             "localhost",
+			Port:             6379,
+			JourneyCacheSize: 20000,
+			JourneyCacheTTL:  time.Hour,
+			Pipeline:         "tx",
+		},
+		Postgres: PostgresConfig{
+			Host: "localhost",
+			DB:   "postgres",
+			User: "postgres",
+		},
+		MQTT: MQTTConfig{
+			Broker:          "mqtt.hsl.fi",
+			Port:            8883,
+			Topic:           "/hfp/v2/journey/+/+/#",
+			Workers:         10,
+			StagingSize:     1024,
+			Sinks:           "redis",
+			BatchSize:       100,
+			BatchInterval:   50 * time.Millisecond,
+			Overflow:        "drop",
+			SpillDir:        filepath.Join(os.TempDir(), "mqttspill"),
+			SpillMaxMB:      512,
+			Delivery:        "at-most-once",
+			MetricsAddr:     ":2112",
+			ShutdownTimeout: 30 * time.Second,
+			LogLevel:        "warn",
+		},
+		Validation: ValidationConfig{
+			Rules:  "required,bbox,speed,acc,skew,heading",
+			BBox:   "59.8,23.8,60.8,25.8",
+			MaxSpd: 50,
+			MaxAcc: 10,
+			Skew:   5 * time.Minute,
+
+			Required:   "VP=tsi,lat,long;DUE,ARR,DEP,ARS,PDE,PAS,WAIT=tsi,stop",
+			HeadingMin: 0,
+			HeadingMax: 360,
+		},
+		Journeys: JourneysConfig{
+			IdleAfter:    2 * time.Minute,
+			FinishAfter:  30 * time.Minute,
+			ReapInterval: 30 * time.Second,
+		},
+		Vehicles: VehiclesConfig{
+			IdleAfter: 5 * time.Minute,
+		},
+		TimeSeries: TimeSeriesConfig{
+			Series:          DefaultSeries(),
+			Lateness:        10 * time.Second,
+			RollupBy:        "route,mode,oper",
+			RollupBucket:    time.Minute,
+			RollupRetention: 24 * time.Hour,
+		},
+		Locations: LocationsConfig{
+			Addr:            ":2152",
+			MaxConns:        100,
+			ShutdownTimeout: 10 * time.Second,
+			LogLevel:        "debug",
+		},
+		Tiles: TilesConfig{
+			Addr:            ":2151",
+			Dir:             "/tiles",
+			ShutdownTimeout: 10 * time.Second,
+			LogLevel:        "debug",
+		},
+	}
+}
+
+// Validate - checks settings that would otherwise fail (or misbehave) at runtime
+func (c *Config) Validate() error {
+
+	if _, err := c.MQTT.Subscriptions(); err != nil {
+		return fmt.Errorf("mqtt.topics: %w", err)
+	}
+
+	switch {
+	case c.MQTT.Workers < 1:
+		return fmt.Errorf("mqtt.workers must be at least 1, got %d", c.MQTT.Workers)
+	case c.MQTT.StagingSize < 1:
+		return fmt.Errorf("mqtt.staging_size must be at least 1, got %d", c.MQTT.StagingSize)
+	case c.MQTT.Overflow != "drop" && c.MQTT.Overflow != "spill":
+		return fmt.Errorf("mqtt.overflow must be drop or spill, got %q", c.MQTT.Overflow)
+	case c.MQTT.Delivery != "at-most-once" && c.MQTT.Delivery != "at-least-once":
+		return fmt.Errorf("mqtt.delivery must be at-most-once or at-least-once, got %q", c.MQTT.Delivery)
+	case c.MQTT.Delivery == "at-least-once" && c.MQTT.ClientID == "":
+		return fmt.Errorf("mqtt.client_id must be set for at-least-once delivery")
+	case c.MQTT.SpillMaxMB < 1:
+		return fmt.Errorf("mqtt.spill_max_mb must be at least 1, got %d", c.MQTT.SpillMaxMB)
+	case c.MQTT.BatchSize < 1:
+		return fmt.Errorf("mqtt.batch_size must be at least 1, got %d", c.MQTT.BatchSize)
+	case c.MQTT.BatchInterval <= 0:
+		return fmt.Errorf("mqtt.batch_interval must be positive, got %s", c.MQTT.BatchInterval)
+	case c.Redis.Pipeline != "tx" && c.Redis.Pipeline != "plain":
+		return fmt.Errorf("redis.pipeline must be tx or plain, got %q", c.Redis.Pipeline)
+	case c.Redis.JourneyCacheSize < 1:
+		return fmt.Errorf("redis.journey_cache_size must be at least 1, got %d", c.Redis.JourneyCacheSize)
+	case c.Redis.JourneyCacheTTL <= 0:
+		return fmt.Errorf("redis.journey_cache_ttl must be positive, got %s", c.Redis.JourneyCacheTTL)
+	case c.Journeys.IdleAfter <= 0 || c.Journeys.ReapInterval <= 0:
+		return fmt.Errorf("journeys.idle_after and journeys.reap_interval must be positive")
+	case c.Journeys.FinishAfter < c.Journeys.IdleAfter:
+		return fmt.Errorf("journeys.finish_after (%s) must be at least journeys.idle_after (%s)", c.Journeys.FinishAfter, c.Journeys.IdleAfter)
+	case c.Vehicles.IdleAfter <= 0:
+		return fmt.Errorf("vehicles.idle_after must be positive, got %s", c.Vehicles.IdleAfter)
+	case c.Locations.MaxConns < 1:
+		return fmt.Errorf("locations.max_conns must be at least 1, got %d", c.Locations.MaxConns)
+	}
+
+	for name, port := range map[string]int{"redis.port": c.Redis.Port, "mqtt.port": c.MQTT.Port} {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("%s must be on [1, 65535], got %d", name, port)
+		}
+	}
+
+	for name, level := range map[string]string{
+		"mqtt.log_level": c.MQTT.LogLevel, "locations.log_level": c.Locations.LogLevel, "tiles.log_level": c.Tiles.LogLevel,
+	} {
+		if _, err := log.ParseLevel(level); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	if err := ValidateSeries(c.TimeSeries.Series); err != nil {
+		return fmt.Errorf("timeseries: %w", err)
+	}
+
+	if c.TimeSeries.Lateness < 0 {
+		return fmt.Errorf("timeseries.lateness can't be negative")
+	}
+
+	for _, dim := range c.TimeSeries.rollupDims() {
+		if RollupDims[dim] == nil {
+			return fmt.Errorf("timeseries.rollup_by: unknown dimension %q", dim)
+		}
+	}
+
+	if c.TimeSeries.RollupBucket < time.Second || c.TimeSeries.RollupRetention < c.TimeSeries.RollupBucket {
+		return fmt.Errorf("timeseries.rollup_bucket must be at least 1s, and no longer than timeseries.rollup_retention")
+	}
+
+	if _, err := NewValidatorFromConfig(c.Validation); err != nil {
+		return fmt.Errorf("validation: %w", err)
+	}
+
+	return nil
+}
+
+// Subscriptions - returns the topic filters to subscribe to, see Topics
+func (c *MQTTConfig) Subscriptions() ([]Subscription, error) {
+	if c.Topics != "" {
+		return ParseSubscriptions(c.Topics)
+	}
+	return ParseSubscriptions(c.Topic)
+}
+
+// Print - writes the config as YAML, w. secrets redacted
+func (c *Config) Print(w io.Writer) error {
+
+	redacted := *c
+	if redacted.Redis.Password != "" {
+		redacted.Redis.Password = "<redacted>"
+	}
+
+	b, err := yaml.Marshal(&redacted)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+// LoadConfig - registers `-config`, `-print-config`, and a flag for each setting
+// on fs, parses args, and loads the config. Commands w. their own flags pass
+// flag.CommandLine; the YAML file may also be set w. `CONFIG_FILE`.
+//
+// W. `-print-config` the effective config is written to stdout and the process
+// exits, e.g. to check what a container would run with
+func LoadConfig(fs *flag.FlagSet, args []string) (*Config, error) {
+
+	cfg := DefaultConfig()
+
+	path := fs.String("config", os.Getenv("CONFIG_FILE"), "Path to a YAML config file")
+	printConfig := fs.Bool("print-config", false, "Print the effective config and exit")
+
+	// Flags are parsed first but applied last, s.t. they override the file and env
+	flags := make(map[string]*settingFlag)
+	walkSettings(reflect.ValueOf(cfg).Elem(), "", func(name string, v reflect.Value, f reflect.StructField) {
+		flags[name] = &settingFlag{}
+		fs.Var(flags[name], name, fmt.Sprintf("%s (env %s)", name, f.Tag.Get("env")))
+	})
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *path != "" {
+		b, err := ioutil.ReadFile(*path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.UnmarshalStrict(b, cfg); err != nil {
+			return nil, fmt.Errorf("%s: %w", *path, err)
+		}
+	}
+
+	var err error
+	walkSettings(reflect.ValueOf(cfg).Elem(), "", func(name string, v reflect.Value, f reflect.StructField) {
+		if err != nil {
+			return
+		}
+		if env, ok := os.LookupEnv(f.Tag.Get("env")); ok && env != "" {
+			if serr := setSetting(v, env); serr != nil {
+				err = fmt.Errorf("%s: %w", f.Tag.Get("env"), serr)
+			}
+		}
+		if fl := flags[name]; err == nil && fl.set {
+			if serr := setSetting(v, fl.value); serr != nil {
+				err = fmt.Errorf("-%s: %w", name, serr)
+			}
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if *printConfig {
+		cfg.Print(os.Stdout)
+		os.Exit(0)
+	}
+
+	return cfg, nil
+}
+
+// ConfigFromEnv - loads the config w.o. flags, for commands that parse their
+// own arguments
+func ConfigFromEnv() (*Config, error) {
+	return LoadConfig(flag.NewFlagSet("config", flag.ContinueOnError), nil)
+}
+
+// SetLogLevel - sets the logrus level, level is checked by Validate
+func SetLogLevel(level string) {
+	if lvl, err := log.ParseLevel(level); err == nil {
+		log.SetLevel(lvl)
+	}
+}
+
+// settingFlag - flag.Value that holds the raw value until LoadConfig applies it
+type settingFlag struct {
+	value string
+	set   bool
+}
+
+func (f *settingFlag) String() string { return f.value }
+
+func (f *settingFlag) Set(s string) error {
+	f.value, f.set = s, true
+	return nil
+}
+
+// walkSettings - calls fn for each (leaf) setting of v w. its YAML path
+func walkSettings(v reflect.Value, prefix string, fn func(name string, v reflect.Value, f reflect.StructField)) {
+
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if f.Type.Kind() == reflect.Struct {
+			walkSettings(v.Field(i), name, fn)
+			continue
+		}
+
+		// NOTE: Lists (e.g. timeseries.series) are only set from YAML
+		if f.Type.Kind() == reflect.Slice {
+			continue
+		}
+
+		fn(name, v.Field(i), f)
+	}
+}
+
+// setSetting - parses s into the setting v
+func setSetting(v reflect.Value, s string) error {
+
+	// NOTE: Durations are int64s, check for them first
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(i))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package hsldatabridge
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setenv - sets (or w. an empty value, unsets) an env var for the rest of the test
+func setenv(t *testing.T, key, value string) {
+
+	prev, ok := os.LookupEnv(key)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, prev)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+
+	if value == "" {
+		os.Unsetenv(key)
+	} else {
+		os.Setenv(key, value)
+	}
+}
+
+// loadTestConfig - loads the config from yml (if any) and args, as a command would
+func loadTestConfig(t *testing.T, yml string, args ...string) (*Config, error) {
+
+	path := ""
+	if yml != "" {
+		path = filepath.Join(t.TempDir(), "config.yml")
+		if err := ioutil.WriteFile(path, []byte(yml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	return LoadConfig(fs, append([]string{"-config", path}, args...))
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+
+	for _, tc := range []struct {
+		name string
+		yml  string
+		env  string
+		flag string
+		want int
+	}{
+		{name: "default", want: 10},
+		{name: "yaml", yml: "mqtt:\n  workers: 20\n", want: 20},
+		{name: "env over yaml", yml: "mqtt:\n  workers: 20\n", env: "30", want: 30},
+		{name: "flag over env", yml: "mqtt:\n  workers: 20\n", env: "30", flag: "40", want: 40},
+		{name: "flag over yaml", yml: "mqtt:\n  workers: 20\n", flag: "40", want: 40},
+		{name: "flag over default", flag: "40", want: 40},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setenv(t, "MQTT_N_WORKERS", tc.env)
+
+			var args []string
+			if tc.flag != "" {
+				args = []string{"-mqtt.workers", tc.flag}
+			}
+
+			cfg, err := loadTestConfig(t, tc.yml, args...)
+			if err != nil {
+				t.Fatalf("LoadConfig: %+v", err)
+			}
+
+			if cfg.MQTT.Workers != tc.want {
+				t.Errorf("got mqtt.workers %d, want %d", cfg.MQTT.Workers, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigLayers(t *testing.T) {
+
+	setenv(t, "MQTT_BATCH_INTERVAL", "100ms")
+	setenv(t, "REDIS_PIPELINE", "plain")
+	setenv(t, "MQTT_LOG_LEVEL", "")
+	setenv(t, "VALIDATION_MAX_SPD", "")
+
+	yml := "mqtt:\n  log_level: info\n  batch_interval: 1s\nvalidation:\n  max_spd: 40.5\n"
+
+	cfg, err := loadTestConfig(t, yml, "-mqtt.log_level", "error")
+	if err != nil {
+		t.Fatalf("LoadConfig: %+v", err)
+	}
+
+	// Each setting takes its value from the highest layer that sets it, the
+	// rest are left at their defaults
+	want := DefaultConfig()
+	want.MQTT.LogLevel = "error"
+	want.MQTT.BatchInterval = 100 * time.Millisecond
+	want.Redis.Pipeline = "plain"
+	want.Validation.MaxSpd = 40.5
+
+	if cfg.MQTT != want.MQTT || cfg.Redis != want.Redis || cfg.Validation != want.Validation {
+		t.Errorf("LoadConfig\n got: %+v %+v %+v\nwant: %+v %+v %+v",
+			cfg.MQTT, cfg.Redis, cfg.Validation, want.MQTT, want.Redis, want.Validation)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+
+	setenv(t, "MQTT_N_WORKERS", "")
+	setenv(t, "REDIS_PORT", "")
+
+	for _, tc := range []struct {
+		name string
+		yml  string
+		env  string
+		args []string
+	}{
+		{name: "unknown yaml key", yml: "mqtt:\n  wrokers: 20\n"},
+		{name: "bad yaml value", yml: "mqtt:\n  workers: many\n"},
+		{name: "bad env value", env: "many"},
+		{name: "bad flag value", args: []string{"-mqtt.batch_interval", "50"}},
+		{name: "unknown flag", args: []string{"-mqtt.wrokers", "20"}},
+		{name: "fails validation", args: []string{"-redis.port", "0"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setenv(t, "MQTT_N_WORKERS", tc.env)
+
+			if _, err := loadTestConfig(t, tc.yml, tc.args...); err == nil {
+				t.Errorf("LoadConfig: got nil error")
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("DefaultConfig().Validate(): %+v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		modify func(c *Config)
+	}{
+		{"no workers", func(c *Config) { c.MQTT.Workers = 0 }},
+		{"no staging", func(c *Config) { c.MQTT.StagingSize = 0 }},
+		{"unknown overflow", func(c *Config) { c.MQTT.Overflow = "block" }},
+		{"unknown delivery", func(c *Config) { c.MQTT.Delivery = "exactly-once" }},
+		{"at-least-once w.o. client id", func(c *Config) { c.MQTT.Delivery = "at-least-once" }},
+		{"no spill space", func(c *Config) { c.MQTT.SpillMaxMB = 0 }},
+		{"empty batches", func(c *Config) { c.MQTT.BatchSize = 0 }},
+		{"no batch interval", func(c *Config) { c.MQTT.BatchInterval = 0 }},
+		{"bad topic", func(c *Config) { c.MQTT.Topics = "/hfp/v2/#=3" }},
+		{"unknown pipeline", func(c *Config) { c.Redis.Pipeline = "multi" }},
+		{"empty journey cache", func(c *Config) { c.Redis.JourneyCacheSize = 0 }},
+		{"finish before idle", func(c *Config) { c.Journeys.FinishAfter = time.Minute }},
+		{"no vehicle idle", func(c *Config) { c.Vehicles.IdleAfter = 0 }},
+		{"no connections", func(c *Config) { c.Locations.MaxConns = 0 }},
+		{"port out of range", func(c *Config) { c.MQTT.Port = 70000 }},
+		{"bad log level", func(c *Config) { c.Tiles.LogLevel = "loud" }},
+		{"duplicate series", func(c *Config) { c.TimeSeries.Series = append(c.TimeSeries.Series, c.TimeSeries.Series[0]) }},
+		{"negative lateness", func(c *Config) { c.TimeSeries.Lateness = -time.Second }},
+		{"unknown rollup dimension", func(c *Config) { c.TimeSeries.RollupBy = "route,colour" }},
+		{"rollup bucket past retention", func(c *Config) { c.TimeSeries.RollupBucket = 48 * time.Hour }},
+		{"bad bbox", func(c *Config) { c.Validation.BBox = "59.8,23.8,60.8" }},
+		{"unknown rule", func(c *Config) { c.Validation.Rules = "required,teleport" }},
+		{"unknown required field", func(c *Config) { c.Validation.Required = "VP=tsi,colour" }},
+		{"heading range reversed", func(c *Config) { c.Validation.HeadingMin, c.Validation.HeadingMax = 360, 0 }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := DefaultConfig()
+			tc.modify(c)
+
+			if err := c.Validate(); err == nil {
+				t.Errorf("Validate: got nil error")
+			}
+		})
+	}
+
+	// Set together, valid
+	c := DefaultConfig()
+	c.MQTT.Delivery, c.MQTT.ClientID = "at-least-once", "hsldatabridge-1"
+
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate w. at-least-once and a client id: %+v", err)
+	}
+}
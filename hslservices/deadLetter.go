@@ -0,0 +1,218 @@
+package hsldatabridge
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+	log "github.com/sirupsen/logrus"
+)
+
+// deadLetterStream - default stream for messages that failed to decode
+const deadLetterStream = "mqtt:deadletter"
+
+// deadLetterPage - entries read per XRANGE when scanning the stream
+const deadLetterPage = 1000
+
+// lexerErrorDetail - strips the wrapper and position from ffjson errors s.t. the
+// same mistake at different offsets is grouped together, e.g.
+//
+// ffjson error: (*errors.errorString)cannot unmarshal tok:integer into Go value for string offset=18 line=1 char=18
+var lexerErrorDetail = regexp.MustCompile(`^ffjson error: \(\*[\w.]+\)|\s+offset=\d+ line=\d+ char=\d+$`)
+
+// DeadLetter - A message that failed to decode, as captured by DeadLetterQueue
+type DeadLetter struct {
+	ID        string
+	Topic     string
+	Payload   []byte
+	Error     string
+	ErrorType string
+	Received  time.Time
+}
+
+// StagedMessage - returns the original message, e.g. to decode it again
+func (d *DeadLetter) StagedMessage() *StagedMessage {
+	return &StagedMessage{Topic: d.Topic, Payload: d.Payload, Received: d.Received}
+}
+
+// DeadLetterQueue - A bounded Redis stream of MQTT messages that failed to
+// decode, kept w. the error and topic s.t. they can be inspected and re-driven
+// once the decoder is fixed (see cmd/deadletter)
+type DeadLetterQueue struct {
+	Stream string
+	MaxLen int64 // Approx. max length of the stream, oldest entries are trimmed
+
+	client *redis.Client
+}
+
+// NewDeadLetterQueue - creates a queue on the default stream, `mqtt:deadletter`,
+// holding approx. the last 100,000 failures
+func NewDeadLetterQueue(client *redis.Client) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		Stream: deadLetterStream,
+		MaxLen: 100000,
+		client: client,
+	}
+}
+
+// Close - closes the Redis client
+func (q *DeadLetterQueue) Close() error {
+	return q.client.Close()
+}
+
+// ErrorType - groups decode errors by cause rather than by message, e.g. all
+// numeric routes share a type regardless of where in the body they're found
+func ErrorType(err error) string {
+	switch err := err.(type) {
+	case *MQTTValidationError:
+		return fmt.Sprintf("validation: %s", err.Message)
+	default:
+		if strings.HasPrefix(err.Error(), "ffjson error:") {
+			return fmt.Sprintf("decode: %s", lexerErrorDetail.ReplaceAllString(err.Error(), ""))
+		}
+		return fmt.Sprintf("%T", err)
+	}
+}
+
+// Push - writes a failed message to the stream w. the error that caused it
+func (q *DeadLetterQueue) Push(ctx context.Context, msg *StagedMessage, cause error) error {
+	return q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream:       q.Stream,
+		MaxLenApprox: q.MaxLen,
+		Values: []interface{}{
+			"topic", msg.Topic,
+			"payload", msg.Payload,
+			"error", cause.Error(),
+			"errtype", ErrorType(cause),
+			"recv", msg.Received.UnixNano() / int64(time.Millisecond),
+		},
+	}).Err()
+}
+
+// List - returns up to count entries, oldest first, w. IDs after start (exclusive);
+// use "" to start from the beginning of the stream
+func (q *DeadLetterQueue) List(ctx context.Context, start string, count int64) ([]*DeadLetter, error) {
+
+	if start == "" {
+		start = "-"
+	} else {
+		start = "(" + start
+	}
+
+	msgs, err := q.client.XRangeN(ctx, q.Stream, start, "+", count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]*DeadLetter, len(msgs))
+
+	for i, msg := range msgs {
+		str := func(key string) string {
+			s, _ := msg.Values[key].(string)
+			return s
+		}
+
+		recv, _ := strconv.ParseInt(str("recv"), 10, 64)
+
+		letters[i] = &DeadLetter{
+			ID:        msg.ID,
+			Topic:     str("topic"),
+			Payload:   []byte(str("payload")),
+			Error:     str("error"),
+			ErrorType: str("errtype"),
+			Received:  time.Unix(0, recv*int64(time.Millisecond)),
+		}
+	}
+
+	return letters, nil
+}
+
+// Scan - calls fn for each entry in the stream, oldest first, stopping at
+// the first error
+func (q *DeadLetterQueue) Scan(ctx context.Context, fn func(*DeadLetter) error) error {
+
+	start := ""
+
+	for {
+		letters, err := q.List(ctx, start, deadLetterPage)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range letters {
+			if err := fn(d); err != nil {
+				return err
+			}
+		}
+
+		if len(letters) < deadLetterPage {
+			return nil
+		}
+
+		start = letters[len(letters)-1].ID
+	}
+}
+
+// ErrorTypes - returns the count of entries for each error type
+func (q *DeadLetterQueue) ErrorTypes(ctx context.Context) (map[string]int, error) {
+
+	counts := make(map[string]int)
+
+	err := q.Scan(ctx, func(d *DeadLetter) error {
+		counts[d.ErrorType]++
+		return nil
+	})
+
+	return counts, err
+}
+
+// Redrive - decodes each entry (of errType, or all entries if "") again, checks it
+// against v (if set), and writes it to every sink, removing it from the stream once
+// written. Entries that still fail to decode, are rejected by v, or fail to write,
+// are left in place
+func (q *DeadLetterQueue) Redrive(ctx context.Context, sinks []Sink, v *Validator, errType string) (redriven int, rejected int, failed int, err error) {
+
+	err = q.Scan(ctx, func(d *DeadLetter) error {
+
+		if errType != "" && d.ErrorType != errType {
+			return nil
+		}
+
+		e := &EventHolder{}
+		if err := DeserializeMQTTBody(d.Topic, d.Payload, e); err != nil {
+			log.WithFields(log.Fields{"ID": d.ID, "ErrorType": ErrorType(err)}).Debugf("Failed to Decode: %+v", err)
+			failed++
+			return nil
+		}
+
+		// NOTE: Validate against the time the message was first received, not now,
+		// s.t. the skew rule doesn't reject every re-driven entry
+		if v != nil {
+			if err := v.Validate(e, d.Received); err != nil {
+				log.WithFields(log.Fields{"ID": d.ID}).Debugf("Failed Validation: %+v", err)
+				rejected++
+				return nil
+			}
+		}
+
+		for _, sink := range sinks {
+			if err := sink.Write(ctx, []*EventHolder{e}); err != nil {
+				// Sink is likely down, stop rather than fail every remaining entry
+				return err
+			}
+		}
+
+		if err := q.client.XDel(ctx, q.Stream, d.ID).Err(); err != nil {
+			return err
+		}
+
+		redriven++
+		return nil
+	})
+
+	return redriven, rejected, failed, err
+}
@@ -1,8 +1,11 @@
 package hsldatabridge
 
-// MQTTValidationError -
+// MQTTValidationError - An event that decoded but was rejected, Rule is the
+// reason code (e.g. `bbox`, see validation.go), empty for structural errors
+// such as an unknown event type or an invalid topic
 type MQTTValidationError struct {
 	Message string
+	Rule    RuleCode
 }
 
 func (e MQTTValidationError) Error() string {
@@ -4,7 +4,9 @@ import (
 	"crypto/md5"
 	"fmt"
 	"io"
-	log "github.com/sirupsen/logrus"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Event - The Event body contains a single key that indicates event type, rather
@@ -17,7 +19,8 @@ type Event struct {
 	ODay            string  `json:"oday"`  // Operating day of the trip. The exact time when an operating day ends depends on the route.
 	Direction       string  `json:"dir"`   // Route direction of the trip
 	VehID           int     `json:"veh"`   // Vehicle number that can be seen painted on the side of the vehicle - Can be String OR Int
-	Timestamp       int64   `json:"tsi"`   // UTC timestamp with millisecond precision from the vehicle in UnixTime
+	Timestamp       int64   `json:"tsi"`   // UTC timestamp from the vehicle in UnixTime, in seconds
+	Tst             string  `json:"tst"`   // UTC timestamp with millisecond precision from the vehicle, ISO 8601
 	Lat             float64 `json:"lat"`   // WGS 84 latitude in degrees.
 	Lng             float64 `json:"long"`  // WGS 84 longitude in degrees.
 	Heading         int     `json:"hdg"`   // Heading of the vehicle, in degrees (⁰) starting clockwise from geographic north.
@@ -26,8 +29,113 @@ type Event struct {
 	Spd             float32 `json:"spd"`   // Speed of the vehicle, in meters per second (m/s).
 	Acc             float32 `json:"acc"`   // Acceleration (m/s^2), calculated from the speed on this and the previous message
 	RouteID         string  `json:"route"` // ID of the route the vehicle is currently running on. Matches route_id in the topic.
-	Stop            int     `json:"stop"`
-	Occupancy       int     `json:"occu"` // Integer describing passenger occupancy level of the vehicle on [0, 100]
+	Stop            OptInt  `json:"stop"`  // ID of the stop the vehicle is at, `null` if not at a stop
+	Odometer        OptInt  `json:"odo"`   // Odometer reading in meters since the start of the journey, often `null`
+	Occupancy       int     `json:"occu"`  // Integer describing passenger occupancy level of the vehicle on [0, 100]
+}
+
+// Topic - The MQTT topic an event was published on. HFP v2 topics carry several
+// attributes of the journey that are often missing from the message body, e.g.
+//
+// /hfp/v2/journey/ongoing/vp/bus/0018/00423/2159/2/Matinkylä (M)/09:32/2442201/3/60;24/16/58/67
+//
+// Empty levels (e.g. deadrun vehicles w.o. a route) are left as empty strings.
+// Docs: https://digitransit.fi/en/developers/apis/4-realtime-api/vehicle-positions/#the-topic
+type Topic struct {
+	JourneyType   string `json:"journey_type"`  // `journey` for vehicles in service, `deadrun` for vehicles not in service
+	TemporalType  string `json:"temporal_type"` // `ongoing` or `upcoming`
+	EventType     string `json:"event_type"`    // Type of the event, e.g. `vp`, `dep`, `arr`
+	TransportMode string `json:"mode"`          // Type of the vehicle, e.g. `bus`, `tram`, `metro`
+	OperatorID    string `json:"oper"`          // Operator ID, zero padded to 4 digits
+	VehicleNumber string `json:"veh"`           // Vehicle number, zero padded to 5 digits
+	RouteID       string `json:"route"`         // ID of the route the vehicle is running on
+	DirectionID   string `json:"dir"`           // Route direction of the trip, `1` or `2`
+	Headsign      string `json:"headsign"`      // Destination name, e.g. `Matinkylä (M)`
+	StartTime     string `json:"start"`         // Scheduled start time of the trip, HH:mm
+	NextStop      string `json:"next_stop"`     // ID of the stop the vehicle is currently at or next, `EOL` at end of line
+	GeohashLevel  int    `json:"gh_level"`      // Indicates which part of the geohash changed since the last message
+	Geohash       string `json:"gh"`            // Location of the vehicle as a truncated geohash, e.g. `60;24/16/58/67`
+}
+
+// ParseTopic - splits a HFP v2 MQTT topic into a Topic; returns an
+// MQTTValidationError if the topic is not a HFP v2 topic
+func ParseTopic(topic string) (*Topic, error) {
+
+	// NOTE: topic begins w. a leading `/`, levels[0] is always empty
+	levels := strings.Split(topic, "/")
+
+	if len(levels) < 9 || levels[1] != "hfp" || levels[2] != "v2" {
+		return nil, &MQTTValidationError{Message: fmt.Sprintf("Custom error; Invalid topic %s", topic)}
+	}
+
+	// Index the topic levels so that (possibly missing) levels
+	// past the vehicle number default to ""
+	level := func(i int) string {
+		if i < len(levels) {
+			return levels[i]
+		}
+		return ""
+	}
+
+	t := &Topic{
+		JourneyType:   levels[3],
+		TemporalType:  levels[4],
+		EventType:     levels[5],
+		TransportMode: levels[6],
+		OperatorID:    levels[7],
+		VehicleNumber: levels[8],
+		RouteID:       level(9),
+		DirectionID:   level(10),
+		Headsign:      level(11),
+		StartTime:     level(12),
+		NextStop:      level(13),
+	}
+
+	// Geohash is split over the remaining levels, e.g. `3/60;24/16/58/67`
+	if ghLevel, err := strconv.Atoi(level(14)); err == nil {
+		t.GeohashLevel = ghLevel
+	}
+
+	if len(levels) > 15 {
+		t.Geohash = strings.Join(levels[15:], "/")
+	}
+
+	return t, nil
+}
+
+// fillFromTopic - sets any journey attributes missing from the message body
+// to the values sent on the topic
+func (e *Event) fillFromTopic(t *Topic) {
+
+	if e.RouteID == "" {
+		e.RouteID = t.RouteID
+	}
+
+	if e.Direction == "" {
+		e.Direction = t.DirectionID
+	}
+
+	if e.Start == "" {
+		e.Start = t.StartTime
+	}
+
+	if e.VehID == 0 {
+		// Zero padded on the topic, e.g. `00423`
+		if vehID, err := strconv.Atoi(t.VehicleNumber); err == nil {
+			e.VehID = vehID
+		}
+	}
+}
+
+// Millis - the vehicle's timestamp in unix ms, from `tst` if sent and valid, else
+// `tsi`; 0 if the event has neither
+func (e *Event) Millis() int64 {
+
+	if t, err := time.Parse(time.RFC3339Nano, e.Tst); err == nil {
+		return t.UnixNano() / int64(time.Millisecond)
+	}
+
+	return e.Timestamp * 1000
 }
 
 // GetEventHash  -
@@ -36,20 +144,205 @@ func (e *Event) GetEventHash() string {
 	// Create a Hash of the Object's key Identifying Features
 	h := md5.New()
 	io.WriteString(h, fmt.Sprintf("%d:%s:%s", e.JrnID, e.RouteID, e.ODay))
-	journeyID := fmt.Sprintf("%x", h.Sum(nil))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
 
-	log.Infof("%d:%s:%s", e.JrnID, e.RouteID, e.ODay)
-	log.Infof(journeyID)
+// StopEvent - Events sent as a vehicle approaches, arrives at, waits at or leaves
+// a stop (DUE, ARR, DEP, ARS, PDE, PAS, WAIT). `Stop` on the core event holds the ID
+// of the stop.
+type StopEvent struct {
+	Event
+	ScheduledArrival   string `json:"ttarr"` // Scheduled arrival time at the stop, ISO 8601 UTC
+	ScheduledDeparture string `json:"ttdep"` // Scheduled departure time from the stop, ISO 8601 UTC
+}
+
+// DoorEvent - Events sent when the doors of the vehicle open or close (DOO, DOC)
+type DoorEvent struct {
+	Event
+	DoorStatus OptInt `json:"drst"` // 0 if all doors are closed, 1 if any door is open, often `null`
+}
+
+// TrafficLightEvent - Events sent when a vehicle requests traffic light priority
+// and when the junction acknowledges the request (TLR, TLA)
+type TrafficLightEvent struct {
+	Event
+	RequestID      int    `json:"tlp-requestid"`      // ID of the priority request
+	RequestType    string `json:"tlp-requesttype"`    // One of NORMAL, DOOR_CLOSE, DOOR_OPEN, ADVANCE
+	PriorityLevel  string `json:"tlp-prioritylevel"`  // One of normal, high, norequest
+	Reason         string `json:"tlp-reason"`         // One of GLOBAL, AHEAD, LINE, PRIOEXEP
+	AttemptSeq     int    `json:"tlp-att-seq"`        // Sequence of the request attempt, starts from 1
+	Decision       string `json:"tlp-decision"`       // ACK or NAK, only sent on TLA
+	JunctionID     int    `json:"sid"`                // ID of the junction
+	SignalGroupID  int    `json:"signal-groupid"`     // ID of the signal group at the junction
+	SignalGroupNbr int    `json:"tlp-signalgroupnbr"` // Signal group number
+	LineConfigID   int    `json:"tlp-line-configid"`  // Line configuration ID
+	PointConfigID  int    `json:"tlp-point-configid"` // Point configuration ID
+	Frequency      int    `json:"tlp-frequency"`      // Radio frequency used for the request
+	Protocol       string `json:"tlp-protocol"`       // MQTT or KAR
+}
+
+// SignOnEvent - Events sent as a driver signs in/out of a vehicle (DA, DOUT), selects
+// or leaves a block (BA, BOUT), or a vehicle signs in/out of a journey (VJA, VJOUT)
+type SignOnEvent struct {
+	Event
+	DriverType int `json:"dr-type"` // Type of the driver sign on, only sent on DA, DOUT
+}
+
+// EventType - The top-level key of an MQTT message body
+type EventType string
 
-	return journeyID
+// Event types published on HFP v2, see docs for a description of each
+const (
+	EventTypeVP    EventType = "VP"
+	EventTypeDUE   EventType = "DUE"
+	EventTypeARR   EventType = "ARR"
+	EventTypeDEP   EventType = "DEP"
+	EventTypeARS   EventType = "ARS"
+	EventTypePDE   EventType = "PDE"
+	EventTypePAS   EventType = "PAS"
+	EventTypeWAIT  EventType = "WAIT"
+	EventTypeDOO   EventType = "DOO"
+	EventTypeDOC   EventType = "DOC"
+	EventTypeTLR   EventType = "TLR"
+	EventTypeTLA   EventType = "TLA"
+	EventTypeDA    EventType = "DA"
+	EventTypeDOUT  EventType = "DOUT"
+	EventTypeBA    EventType = "BA"
+	EventTypeBOUT  EventType = "BOUT"
+	EventTypeVJA   EventType = "VJA"
+	EventTypeVJOUT EventType = "VJOUT"
+)
 
+// eventTypes - every event type above, e.g. to check the types named in config
+var eventTypes = map[EventType]bool{
+	EventTypeVP: true, EventTypeDUE: true, EventTypeARR: true, EventTypeDEP: true, EventTypeARS: true,
+	EventTypePDE: true, EventTypePAS: true, EventTypeWAIT: true, EventTypeDOO: true, EventTypeDOC: true,
+	EventTypeTLR: true, EventTypeTLA: true, EventTypeDA: true, EventTypeDOUT: true, EventTypeBA: true,
+	EventTypeBOUT: true, EventTypeVJA: true, EventTypeVJOUT: true,
 }
 
 // EventHolder is a struct used to capture the top-level of the MQTT
 // message (MsgType) without extracting to rawJSON && reflecting.
 //
-// 4/24/2021: This struct is a placeholder for a struct
-// containing all possible message types, VP is the most commmon....
+// Each message sets exactly one of the event fields, use `Type()` to
+// check which, and `Event()` to access the fields shared by all types.
 type EventHolder struct {
-	VP Event `json:"VP"`
+	VP    *Event             `json:"VP,omitempty"`
+	DUE   *StopEvent         `json:"DUE,omitempty"`
+	ARR   *StopEvent         `json:"ARR,omitempty"`
+	DEP   *StopEvent         `json:"DEP,omitempty"`
+	ARS   *StopEvent         `json:"ARS,omitempty"`
+	PDE   *StopEvent         `json:"PDE,omitempty"`
+	PAS   *StopEvent         `json:"PAS,omitempty"`
+	WAIT  *StopEvent         `json:"WAIT,omitempty"`
+	DOO   *DoorEvent         `json:"DOO,omitempty"`
+	DOC   *DoorEvent         `json:"DOC,omitempty"`
+	TLR   *TrafficLightEvent `json:"TLR,omitempty"`
+	TLA   *TrafficLightEvent `json:"TLA,omitempty"`
+	DA    *SignOnEvent       `json:"DA,omitempty"`
+	DOUT  *SignOnEvent       `json:"DOUT,omitempty"`
+	BA    *SignOnEvent       `json:"BA,omitempty"`
+	BOUT  *SignOnEvent       `json:"BOUT,omitempty"`
+	VJA   *SignOnEvent       `json:"VJA,omitempty"`
+	VJOUT *SignOnEvent       `json:"VJOUT,omitempty"`
+	Topic *Topic             `json:"topic,omitempty"` // Parsed from the MQTT topic, never sent in the message body
+
+	// Coerced - fields of the body sent w. an unexpected type and converted by
+	// the tolerant decoder, e.g. `veh:string->int`; see decodeTolerant
+	Coerced []string `json:"-"`
+}
+
+// Type - returns the type of the event held, "" if the body didn't contain
+// any known event type
+func (h *EventHolder) Type() EventType {
+	t, _ := h.unwrap()
+	return t
+}
+
+// Event - returns the fields shared by all event types, nil if the body
+// didn't contain any known event type
+func (h *EventHolder) Event() *Event {
+	_, e := h.unwrap()
+	return e
+}
+
+// unwrap - returns the type and core of the first event field set
+func (h *EventHolder) unwrap() (EventType, *Event) {
+	switch {
+	case h.VP != nil:
+		return EventTypeVP, h.VP
+	case h.DUE != nil:
+		return EventTypeDUE, &h.DUE.Event
+	case h.ARR != nil:
+		return EventTypeARR, &h.ARR.Event
+	case h.DEP != nil:
+		return EventTypeDEP, &h.DEP.Event
+	case h.ARS != nil:
+		return EventTypeARS, &h.ARS.Event
+	case h.PDE != nil:
+		return EventTypePDE, &h.PDE.Event
+	case h.PAS != nil:
+		return EventTypePAS, &h.PAS.Event
+	case h.WAIT != nil:
+		return EventTypeWAIT, &h.WAIT.Event
+	case h.DOO != nil:
+		return EventTypeDOO, &h.DOO.Event
+	case h.DOC != nil:
+		return EventTypeDOC, &h.DOC.Event
+	case h.TLR != nil:
+		return EventTypeTLR, &h.TLR.Event
+	case h.TLA != nil:
+		return EventTypeTLA, &h.TLA.Event
+	case h.DA != nil:
+		return EventTypeDA, &h.DA.Event
+	case h.DOUT != nil:
+		return EventTypeDOUT, &h.DOUT.Event
+	case h.BA != nil:
+		return EventTypeBA, &h.BA.Event
+	case h.BOUT != nil:
+		return EventTypeBOUT, &h.BOUT.Event
+	case h.VJA != nil:
+		return EventTypeVJA, &h.VJA.Event
+	case h.VJOUT != nil:
+		return EventTypeVJOUT, &h.VJOUT.Event
+	}
+	return "", nil
+}
+
+// StopEvent - returns the held event if it is one of the stop event types
+func (h *EventHolder) StopEvent() *StopEvent {
+	for _, e := range []*StopEvent{h.DUE, h.ARR, h.DEP, h.ARS, h.PDE, h.PAS, h.WAIT} {
+		if e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// DoorEvent - returns the held event if it is one of the door event types
+func (h *EventHolder) DoorEvent() *DoorEvent {
+	if h.DOO != nil {
+		return h.DOO
+	}
+	return h.DOC
+}
+
+// TrafficLightEvent - returns the held event if it is one of the traffic light
+// priority event types
+func (h *EventHolder) TrafficLightEvent() *TrafficLightEvent {
+	if h.TLR != nil {
+		return h.TLR
+	}
+	return h.TLA
+}
+
+// SignOnEvent - returns the held event if it is one of the driver, block or
+// journey sign on/off event types
+func (h *EventHolder) SignOnEvent() *SignOnEvent {
+	for _, e := range []*SignOnEvent{h.DA, h.DOUT, h.BA, h.BOUT, h.VJA, h.VJOUT} {
+		if e != nil {
+			return e
+		}
+	}
+	return nil
 }
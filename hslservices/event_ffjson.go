@@ -10,7 +10,7 @@ import (
 )
 
 // MarshalJSON marshal bytes to json - template
-func (j *Event) MarshalJSON() ([]byte, error) {
+func (j *DoorEvent) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if j == nil {
 		buf.WriteString("null")
@@ -24,7 +24,7 @@ func (j *Event) MarshalJSON() ([]byte, error) {
 }
 
 // MarshalJSONBuf marshal buff to json - template
-func (j *Event) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (j *DoorEvent) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if j == nil {
 		buf.WriteString("null")
 		return nil
@@ -33,7 +33,18 @@ func (j *Event) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{"jrn":`)
+	buf.WriteString(`{"drst":`)
+
+	{
+
+		obj, err = j.DoorStatus.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteString(`,"jrn":`)
 	fflib.FormatBits2(buf, uint64(j.JrnID), 10, j.JrnID < 0)
 	buf.WriteString(`,"oday":`)
 	fflib.WriteJsonString(buf, string(j.ODay))
@@ -43,6 +54,8 @@ func (j *Event) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	fflib.FormatBits2(buf, uint64(j.VehID), 10, j.VehID < 0)
 	buf.WriteString(`,"tsi":`)
 	fflib.FormatBits2(buf, uint64(j.Timestamp), 10, j.Timestamp < 0)
+	buf.WriteString(`,"tst":`)
+	fflib.WriteJsonString(buf, string(j.Tst))
 	buf.WriteString(`,"lat":`)
 	fflib.AppendFloat(buf, float64(j.Lat), 'g', -1, 64)
 	buf.WriteString(`,"long":`)
@@ -60,7 +73,27 @@ func (j *Event) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	buf.WriteString(`,"route":`)
 	fflib.WriteJsonString(buf, string(j.RouteID))
 	buf.WriteString(`,"stop":`)
-	fflib.FormatBits2(buf, uint64(j.Stop), 10, j.Stop < 0)
+
+	{
+
+		obj, err = j.Stop.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteString(`,"odo":`)
+
+	{
+
+		obj, err = j.Odometer.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
 	buf.WriteString(`,"occu":`)
 	fflib.FormatBits2(buf, uint64(j.Occupancy), 10, j.Occupancy < 0)
 	buf.WriteByte('}')
@@ -68,80 +101,92 @@ func (j *Event) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 }
 
 const (
-	ffjtEventbase = iota
-	ffjtEventnosuchkey
+	ffjtDoorEventbase = iota
+	ffjtDoorEventnosuchkey
 
-	ffjtEventJrnID
+	ffjtDoorEventDoorStatus
 
-	ffjtEventODay
+	ffjtDoorEventJrnID
 
-	ffjtEventDirection
+	ffjtDoorEventODay
 
-	ffjtEventVehID
+	ffjtDoorEventDirection
 
-	ffjtEventTimestamp
+	ffjtDoorEventVehID
 
-	ffjtEventLat
+	ffjtDoorEventTimestamp
 
-	ffjtEventLng
+	ffjtDoorEventTst
 
-	ffjtEventHeading
+	ffjtDoorEventLat
 
-	ffjtEventStart
+	ffjtDoorEventLng
 
-	ffjtEventDeltaToSchedule
+	ffjtDoorEventHeading
 
-	ffjtEventSpd
+	ffjtDoorEventStart
 
-	ffjtEventAcc
+	ffjtDoorEventDeltaToSchedule
 
-	ffjtEventRouteID
+	ffjtDoorEventSpd
 
-	ffjtEventStop
+	ffjtDoorEventAcc
 
-	ffjtEventOccupancy
+	ffjtDoorEventRouteID
+
+	ffjtDoorEventStop
+
+	ffjtDoorEventOdometer
+
+	ffjtDoorEventOccupancy
 )
 
-var ffjKeyEventJrnID = []byte("jrn")
+var ffjKeyDoorEventDoorStatus = []byte("drst")
 
-var ffjKeyEventODay = []byte("oday")
+var ffjKeyDoorEventJrnID = []byte("jrn")
 
-var ffjKeyEventDirection = []byte("dir")
+var ffjKeyDoorEventODay = []byte("oday")
 
-var ffjKeyEventVehID = []byte("veh")
+var ffjKeyDoorEventDirection = []byte("dir")
 
-var ffjKeyEventTimestamp = []byte("tsi")
+var ffjKeyDoorEventVehID = []byte("veh")
 
-var ffjKeyEventLat = []byte("lat")
+var ffjKeyDoorEventTimestamp = []byte("tsi")
 
-var ffjKeyEventLng = []byte("long")
+var ffjKeyDoorEventTst = []byte("tst")
 
-var ffjKeyEventHeading = []byte("hdg")
+var ffjKeyDoorEventLat = []byte("lat")
 
-var ffjKeyEventStart = []byte("start")
+var ffjKeyDoorEventLng = []byte("long")
 
-var ffjKeyEventDeltaToSchedule = []byte("dl")
+var ffjKeyDoorEventHeading = []byte("hdg")
 
-var ffjKeyEventSpd = []byte("spd")
+var ffjKeyDoorEventStart = []byte("start")
 
-var ffjKeyEventAcc = []byte("acc")
+var ffjKeyDoorEventDeltaToSchedule = []byte("dl")
 
-var ffjKeyEventRouteID = []byte("route")
+var ffjKeyDoorEventSpd = []byte("spd")
 
-var ffjKeyEventStop = []byte("stop")
+var ffjKeyDoorEventAcc = []byte("acc")
 
-var ffjKeyEventOccupancy = []byte("occu")
+var ffjKeyDoorEventRouteID = []byte("route")
+
+var ffjKeyDoorEventStop = []byte("stop")
+
+var ffjKeyDoorEventOdometer = []byte("odo")
+
+var ffjKeyDoorEventOccupancy = []byte("occu")
 
 // UnmarshalJSON umarshall json - template of ffjson
-func (j *Event) UnmarshalJSON(input []byte) error {
+func (j *DoorEvent) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return j.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
 // UnmarshalJSONFFLexer fast json unmarshall - template ffjson
-func (j *Event) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (j *DoorEvent) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error
-	currentKey := ffjtEventbase
+	currentKey := ffjtDoorEventbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -187,7 +232,7 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffjtEventnosuchkey
+				currentKey = ffjtDoorEventnosuchkey
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
@@ -195,202 +240,235 @@ mainparse:
 
 				case 'a':
 
-					if bytes.Equal(ffjKeyEventAcc, kn) {
-						currentKey = ffjtEventAcc
+					if bytes.Equal(ffjKeyDoorEventAcc, kn) {
+						currentKey = ffjtDoorEventAcc
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'd':
 
-					if bytes.Equal(ffjKeyEventDirection, kn) {
-						currentKey = ffjtEventDirection
+					if bytes.Equal(ffjKeyDoorEventDoorStatus, kn) {
+						currentKey = ffjtDoorEventDoorStatus
 						state = fflib.FFParse_want_colon
 						goto mainparse
 
-					} else if bytes.Equal(ffjKeyEventDeltaToSchedule, kn) {
-						currentKey = ffjtEventDeltaToSchedule
+					} else if bytes.Equal(ffjKeyDoorEventDirection, kn) {
+						currentKey = ffjtDoorEventDirection
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyDoorEventDeltaToSchedule, kn) {
+						currentKey = ffjtDoorEventDeltaToSchedule
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'h':
 
-					if bytes.Equal(ffjKeyEventHeading, kn) {
-						currentKey = ffjtEventHeading
+					if bytes.Equal(ffjKeyDoorEventHeading, kn) {
+						currentKey = ffjtDoorEventHeading
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'j':
 
-					if bytes.Equal(ffjKeyEventJrnID, kn) {
-						currentKey = ffjtEventJrnID
+					if bytes.Equal(ffjKeyDoorEventJrnID, kn) {
+						currentKey = ffjtDoorEventJrnID
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'l':
 
-					if bytes.Equal(ffjKeyEventLat, kn) {
-						currentKey = ffjtEventLat
+					if bytes.Equal(ffjKeyDoorEventLat, kn) {
+						currentKey = ffjtDoorEventLat
 						state = fflib.FFParse_want_colon
 						goto mainparse
 
-					} else if bytes.Equal(ffjKeyEventLng, kn) {
-						currentKey = ffjtEventLng
+					} else if bytes.Equal(ffjKeyDoorEventLng, kn) {
+						currentKey = ffjtDoorEventLng
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'o':
 
-					if bytes.Equal(ffjKeyEventODay, kn) {
-						currentKey = ffjtEventODay
+					if bytes.Equal(ffjKeyDoorEventODay, kn) {
+						currentKey = ffjtDoorEventODay
 						state = fflib.FFParse_want_colon
 						goto mainparse
 
-					} else if bytes.Equal(ffjKeyEventOccupancy, kn) {
-						currentKey = ffjtEventOccupancy
+					} else if bytes.Equal(ffjKeyDoorEventOdometer, kn) {
+						currentKey = ffjtDoorEventOdometer
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyDoorEventOccupancy, kn) {
+						currentKey = ffjtDoorEventOccupancy
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'r':
 
-					if bytes.Equal(ffjKeyEventRouteID, kn) {
-						currentKey = ffjtEventRouteID
+					if bytes.Equal(ffjKeyDoorEventRouteID, kn) {
+						currentKey = ffjtDoorEventRouteID
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 's':
 
-					if bytes.Equal(ffjKeyEventStart, kn) {
-						currentKey = ffjtEventStart
+					if bytes.Equal(ffjKeyDoorEventStart, kn) {
+						currentKey = ffjtDoorEventStart
 						state = fflib.FFParse_want_colon
 						goto mainparse
 
-					} else if bytes.Equal(ffjKeyEventSpd, kn) {
-						currentKey = ffjtEventSpd
+					} else if bytes.Equal(ffjKeyDoorEventSpd, kn) {
+						currentKey = ffjtDoorEventSpd
 						state = fflib.FFParse_want_colon
 						goto mainparse
 
-					} else if bytes.Equal(ffjKeyEventStop, kn) {
-						currentKey = ffjtEventStop
+					} else if bytes.Equal(ffjKeyDoorEventStop, kn) {
+						currentKey = ffjtDoorEventStop
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 't':
 
-					if bytes.Equal(ffjKeyEventTimestamp, kn) {
-						currentKey = ffjtEventTimestamp
+					if bytes.Equal(ffjKeyDoorEventTimestamp, kn) {
+						currentKey = ffjtDoorEventTimestamp
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyDoorEventTst, kn) {
+						currentKey = ffjtDoorEventTst
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'v':
 
-					if bytes.Equal(ffjKeyEventVehID, kn) {
-						currentKey = ffjtEventVehID
+					if bytes.Equal(ffjKeyDoorEventVehID, kn) {
+						currentKey = ffjtDoorEventVehID
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventOccupancy, kn) {
-					currentKey = ffjtEventOccupancy
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventOccupancy, kn) {
+					currentKey = ffjtDoorEventOccupancy
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffjKeyEventStop, kn) {
-					currentKey = ffjtEventStop
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventOdometer, kn) {
+					currentKey = ffjtDoorEventOdometer
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventRouteID, kn) {
-					currentKey = ffjtEventRouteID
+				if fflib.EqualFoldRight(ffjKeyDoorEventStop, kn) {
+					currentKey = ffjtDoorEventStop
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventAcc, kn) {
-					currentKey = ffjtEventAcc
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventRouteID, kn) {
+					currentKey = ffjtDoorEventRouteID
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffjKeyEventSpd, kn) {
-					currentKey = ffjtEventSpd
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventAcc, kn) {
+					currentKey = ffjtDoorEventAcc
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventDeltaToSchedule, kn) {
-					currentKey = ffjtEventDeltaToSchedule
+				if fflib.EqualFoldRight(ffjKeyDoorEventSpd, kn) {
+					currentKey = ffjtDoorEventSpd
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffjKeyEventStart, kn) {
-					currentKey = ffjtEventStart
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventDeltaToSchedule, kn) {
+					currentKey = ffjtDoorEventDeltaToSchedule
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventHeading, kn) {
-					currentKey = ffjtEventHeading
+				if fflib.EqualFoldRight(ffjKeyDoorEventStart, kn) {
+					currentKey = ffjtDoorEventStart
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventLng, kn) {
-					currentKey = ffjtEventLng
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventHeading, kn) {
+					currentKey = ffjtDoorEventHeading
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventLat, kn) {
-					currentKey = ffjtEventLat
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventLng, kn) {
+					currentKey = ffjtDoorEventLng
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffjKeyEventTimestamp, kn) {
-					currentKey = ffjtEventTimestamp
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventLat, kn) {
+					currentKey = ffjtDoorEventLat
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventVehID, kn) {
-					currentKey = ffjtEventVehID
+				if fflib.EqualFoldRight(ffjKeyDoorEventTst, kn) {
+					currentKey = ffjtDoorEventTst
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventDirection, kn) {
-					currentKey = ffjtEventDirection
+				if fflib.EqualFoldRight(ffjKeyDoorEventTimestamp, kn) {
+					currentKey = ffjtDoorEventTimestamp
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventODay, kn) {
-					currentKey = ffjtEventODay
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventVehID, kn) {
+					currentKey = ffjtDoorEventVehID
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventJrnID, kn) {
-					currentKey = ffjtEventJrnID
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventDirection, kn) {
+					currentKey = ffjtDoorEventDirection
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffjtEventnosuchkey
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventODay, kn) {
+					currentKey = ffjtDoorEventODay
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyDoorEventJrnID, kn) {
+					currentKey = ffjtDoorEventJrnID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyDoorEventDoorStatus, kn) {
+					currentKey = ffjtDoorEventDoorStatus
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffjtDoorEventnosuchkey
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -407,52 +485,61 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffjtEventJrnID:
+				case ffjtDoorEventDoorStatus:
+					goto handle_DoorStatus
+
+				case ffjtDoorEventJrnID:
 					goto handle_JrnID
 
-				case ffjtEventODay:
+				case ffjtDoorEventODay:
 					goto handle_ODay
 
-				case ffjtEventDirection:
+				case ffjtDoorEventDirection:
 					goto handle_Direction
 
-				case ffjtEventVehID:
+				case ffjtDoorEventVehID:
 					goto handle_VehID
 
-				case ffjtEventTimestamp:
+				case ffjtDoorEventTimestamp:
 					goto handle_Timestamp
 
-				case ffjtEventLat:
+				case ffjtDoorEventTst:
+					goto handle_Tst
+
+				case ffjtDoorEventLat:
 					goto handle_Lat
 
-				case ffjtEventLng:
+				case ffjtDoorEventLng:
 					goto handle_Lng
 
-				case ffjtEventHeading:
+				case ffjtDoorEventHeading:
 					goto handle_Heading
 
-				case ffjtEventStart:
+				case ffjtDoorEventStart:
 					goto handle_Start
 
-				case ffjtEventDeltaToSchedule:
+				case ffjtDoorEventDeltaToSchedule:
 					goto handle_DeltaToSchedule
 
-				case ffjtEventSpd:
+				case ffjtDoorEventSpd:
 					goto handle_Spd
 
-				case ffjtEventAcc:
+				case ffjtDoorEventAcc:
 					goto handle_Acc
 
-				case ffjtEventRouteID:
+				case ffjtDoorEventRouteID:
 					goto handle_RouteID
 
-				case ffjtEventStop:
+				case ffjtDoorEventStop:
 					goto handle_Stop
 
-				case ffjtEventOccupancy:
+				case ffjtDoorEventOdometer:
+					goto handle_Odometer
+
+				case ffjtDoorEventOccupancy:
 					goto handle_Occupancy
 
-				case ffjtEventnosuchkey:
+				case ffjtDoorEventnosuchkey:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -466,6 +553,31 @@ mainparse:
 		}
 	}
 
+handle_DoorStatus:
+
+	/* handler: j.DoorStatus type=hsldatabridge.OptInt kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tbuf, err := fs.CaptureField(tok)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			err = j.DoorStatus.UnmarshalJSON(tbuf)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
 handle_JrnID:
 
 	/* handler: j.JrnID type=int kind=int quoted=false*/
@@ -608,6 +720,32 @@ handle_Timestamp:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
+handle_Tst:
+
+	/* handler: j.Tst type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Tst = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
 handle_Lat:
 
 	/* handler: j.Lat type=float64 kind=float64 quoted=false*/
@@ -842,29 +980,49 @@ handle_RouteID:
 
 handle_Stop:
 
-	/* handler: j.Stop type=int kind=int quoted=false*/
+	/* handler: j.Stop type=hsldatabridge.OptInt kind=struct quoted=false*/
 
 	{
-		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
-			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tbuf, err := fs.CaptureField(tok)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			err = j.Stop.UnmarshalJSON(tbuf)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
 		}
+		state = fflib.FFParse_after_value
 	}
 
-	{
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Odometer:
 
+	/* handler: j.Odometer type=hsldatabridge.OptInt kind=struct quoted=false*/
+
+	{
 		if tok == fflib.FFTok_null {
 
 		} else {
 
-			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
-
+			tbuf, err := fs.CaptureField(tok)
 			if err != nil {
 				return fs.WrapErr(err)
 			}
 
-			j.Stop = int(tval)
-
+			err = j.Odometer.UnmarshalJSON(tbuf)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
 		}
+		state = fflib.FFParse_after_value
 	}
 
 	state = fflib.FFParse_after_value
@@ -919,7 +1077,7 @@ done:
 }
 
 // MarshalJSON marshal bytes to json - template
-func (j *EventHolder) MarshalJSON() ([]byte, error) {
+func (j *Event) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
 	if j == nil {
 		buf.WriteString("null")
@@ -933,7 +1091,7 @@ func (j *EventHolder) MarshalJSON() ([]byte, error) {
 }
 
 // MarshalJSONBuf marshal buff to json - template
-func (j *EventHolder) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+func (j *Event) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	if j == nil {
 		buf.WriteString("null")
 		return nil
@@ -942,39 +1100,145 @@ func (j *EventHolder) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	var obj []byte
 	_ = obj
 	_ = err
-	buf.WriteString(`{"VP":`)
-
-	{
-
-		err = j.VP.MarshalJSONBuf(buf)
-		if err != nil {
-			return err
-		}
-
-	}
-	buf.WriteByte('}')
-	return nil
-}
+	buf.WriteString(`{"jrn":`)
+	fflib.FormatBits2(buf, uint64(j.JrnID), 10, j.JrnID < 0)
+	buf.WriteString(`,"oday":`)
+	fflib.WriteJsonString(buf, string(j.ODay))
+	buf.WriteString(`,"dir":`)
+	fflib.WriteJsonString(buf, string(j.Direction))
+	buf.WriteString(`,"veh":`)
+	fflib.FormatBits2(buf, uint64(j.VehID), 10, j.VehID < 0)
+	buf.WriteString(`,"tsi":`)
+	fflib.FormatBits2(buf, uint64(j.Timestamp), 10, j.Timestamp < 0)
+	buf.WriteString(`,"tst":`)
+	fflib.WriteJsonString(buf, string(j.Tst))
+	buf.WriteString(`,"lat":`)
+	fflib.AppendFloat(buf, float64(j.Lat), 'g', -1, 64)
+	buf.WriteString(`,"long":`)
+	fflib.AppendFloat(buf, float64(j.Lng), 'g', -1, 64)
+	buf.WriteString(`,"hdg":`)
+	fflib.FormatBits2(buf, uint64(j.Heading), 10, j.Heading < 0)
+	buf.WriteString(`,"start":`)
+	fflib.WriteJsonString(buf, string(j.Start))
+	buf.WriteString(`,"dl":`)
+	fflib.AppendFloat(buf, float64(j.DeltaToSchedule), 'g', -1, 32)
+	buf.WriteString(`,"spd":`)
+	fflib.AppendFloat(buf, float64(j.Spd), 'g', -1, 32)
+	buf.WriteString(`,"acc":`)
+	fflib.AppendFloat(buf, float64(j.Acc), 'g', -1, 32)
+	buf.WriteString(`,"route":`)
+	fflib.WriteJsonString(buf, string(j.RouteID))
+	buf.WriteString(`,"stop":`)
+
+	{
+
+		obj, err = j.Stop.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteString(`,"odo":`)
+
+	{
+
+		obj, err = j.Odometer.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteString(`,"occu":`)
+	fflib.FormatBits2(buf, uint64(j.Occupancy), 10, j.Occupancy < 0)
+	buf.WriteByte('}')
+	return nil
+}
 
 const (
-	ffjtEventHolderbase = iota
-	ffjtEventHoldernosuchkey
+	ffjtEventbase = iota
+	ffjtEventnosuchkey
 
-	ffjtEventHolderVP
+	ffjtEventJrnID
+
+	ffjtEventODay
+
+	ffjtEventDirection
+
+	ffjtEventVehID
+
+	ffjtEventTimestamp
+
+	ffjtEventTst
+
+	ffjtEventLat
+
+	ffjtEventLng
+
+	ffjtEventHeading
+
+	ffjtEventStart
+
+	ffjtEventDeltaToSchedule
+
+	ffjtEventSpd
+
+	ffjtEventAcc
+
+	ffjtEventRouteID
+
+	ffjtEventStop
+
+	ffjtEventOdometer
+
+	ffjtEventOccupancy
 )
 
-var ffjKeyEventHolderVP = []byte("VP")
+var ffjKeyEventJrnID = []byte("jrn")
+
+var ffjKeyEventODay = []byte("oday")
+
+var ffjKeyEventDirection = []byte("dir")
+
+var ffjKeyEventVehID = []byte("veh")
+
+var ffjKeyEventTimestamp = []byte("tsi")
+
+var ffjKeyEventTst = []byte("tst")
+
+var ffjKeyEventLat = []byte("lat")
+
+var ffjKeyEventLng = []byte("long")
+
+var ffjKeyEventHeading = []byte("hdg")
+
+var ffjKeyEventStart = []byte("start")
+
+var ffjKeyEventDeltaToSchedule = []byte("dl")
+
+var ffjKeyEventSpd = []byte("spd")
+
+var ffjKeyEventAcc = []byte("acc")
+
+var ffjKeyEventRouteID = []byte("route")
+
+var ffjKeyEventStop = []byte("stop")
+
+var ffjKeyEventOdometer = []byte("odo")
+
+var ffjKeyEventOccupancy = []byte("occu")
 
 // UnmarshalJSON umarshall json - template of ffjson
-func (j *EventHolder) UnmarshalJSON(input []byte) error {
+func (j *Event) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
 	return j.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
 // UnmarshalJSONFFLexer fast json unmarshall - template ffjson
-func (j *EventHolder) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+func (j *Event) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
 	var err error
-	currentKey := ffjtEventHolderbase
+	currentKey := ffjtEventbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -1020,77 +1284,6674 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffjtEventHoldernosuchkey
+				currentKey = ffjtEventnosuchkey
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
 				switch kn[0] {
 
-				case 'V':
+				case 'a':
 
-					if bytes.Equal(ffjKeyEventHolderVP, kn) {
-						currentKey = ffjtEventHolderVP
+					if bytes.Equal(ffjKeyEventAcc, kn) {
+						currentKey = ffjtEventAcc
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
-				}
+				case 'd':
 
-				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderVP, kn) {
-					currentKey = ffjtEventHolderVP
-					state = fflib.FFParse_want_colon
-					goto mainparse
-				}
+					if bytes.Equal(ffjKeyEventDirection, kn) {
+						currentKey = ffjtEventDirection
+						state = fflib.FFParse_want_colon
+						goto mainparse
 
-				currentKey = ffjtEventHoldernosuchkey
-				state = fflib.FFParse_want_colon
-				goto mainparse
-			}
+					} else if bytes.Equal(ffjKeyEventDeltaToSchedule, kn) {
+						currentKey = ffjtEventDeltaToSchedule
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
 
-		case fflib.FFParse_want_colon:
-			if tok != fflib.FFTok_colon {
-				wantedTok = fflib.FFTok_colon
-				goto wrongtokenerror
-			}
-			state = fflib.FFParse_want_value
-			continue
-		case fflib.FFParse_want_value:
+				case 'h':
 
-			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
-				switch currentKey {
+					if bytes.Equal(ffjKeyEventHeading, kn) {
+						currentKey = ffjtEventHeading
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
 
-				case ffjtEventHolderVP:
-					goto handle_VP
+				case 'j':
 
-				case ffjtEventHoldernosuchkey:
-					err = fs.SkipField(tok)
-					if err != nil {
-						return fs.WrapErr(err)
+					if bytes.Equal(ffjKeyEventJrnID, kn) {
+						currentKey = ffjtEventJrnID
+						state = fflib.FFParse_want_colon
+						goto mainparse
 					}
-					state = fflib.FFParse_after_value
-					goto mainparse
-				}
-			} else {
-				goto wantedvalue
-			}
-		}
-	}
 
-handle_VP:
+				case 'l':
 
-	/* handler: j.VP type=hsldatabridge.Event kind=struct quoted=false*/
+					if bytes.Equal(ffjKeyEventLat, kn) {
+						currentKey = ffjtEventLat
+						state = fflib.FFParse_want_colon
+						goto mainparse
 
-	{
-		if tok == fflib.FFTok_null {
+					} else if bytes.Equal(ffjKeyEventLng, kn) {
+						currentKey = ffjtEventLng
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
 
-		} else {
+				case 'o':
 
-			err = j.VP.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
-			if err != nil {
-				return err
-			}
-		}
-		state = fflib.FFParse_after_value
+					if bytes.Equal(ffjKeyEventODay, kn) {
+						currentKey = ffjtEventODay
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventOdometer, kn) {
+						currentKey = ffjtEventOdometer
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventOccupancy, kn) {
+						currentKey = ffjtEventOccupancy
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'r':
+
+					if bytes.Equal(ffjKeyEventRouteID, kn) {
+						currentKey = ffjtEventRouteID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 's':
+
+					if bytes.Equal(ffjKeyEventStart, kn) {
+						currentKey = ffjtEventStart
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventSpd, kn) {
+						currentKey = ffjtEventSpd
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventStop, kn) {
+						currentKey = ffjtEventStop
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 't':
+
+					if bytes.Equal(ffjKeyEventTimestamp, kn) {
+						currentKey = ffjtEventTimestamp
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventTst, kn) {
+						currentKey = ffjtEventTst
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'v':
+
+					if bytes.Equal(ffjKeyEventVehID, kn) {
+						currentKey = ffjtEventVehID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventOccupancy, kn) {
+					currentKey = ffjtEventOccupancy
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventOdometer, kn) {
+					currentKey = ffjtEventOdometer
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyEventStop, kn) {
+					currentKey = ffjtEventStop
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventRouteID, kn) {
+					currentKey = ffjtEventRouteID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventAcc, kn) {
+					currentKey = ffjtEventAcc
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyEventSpd, kn) {
+					currentKey = ffjtEventSpd
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventDeltaToSchedule, kn) {
+					currentKey = ffjtEventDeltaToSchedule
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyEventStart, kn) {
+					currentKey = ffjtEventStart
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHeading, kn) {
+					currentKey = ffjtEventHeading
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventLng, kn) {
+					currentKey = ffjtEventLng
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventLat, kn) {
+					currentKey = ffjtEventLat
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyEventTst, kn) {
+					currentKey = ffjtEventTst
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyEventTimestamp, kn) {
+					currentKey = ffjtEventTimestamp
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventVehID, kn) {
+					currentKey = ffjtEventVehID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventDirection, kn) {
+					currentKey = ffjtEventDirection
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventODay, kn) {
+					currentKey = ffjtEventODay
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventJrnID, kn) {
+					currentKey = ffjtEventJrnID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffjtEventnosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			}
+
+		case fflib.FFParse_want_colon:
+			if tok != fflib.FFTok_colon {
+				wantedTok = fflib.FFTok_colon
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_value
+			continue
+		case fflib.FFParse_want_value:
+
+			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
+				switch currentKey {
+
+				case ffjtEventJrnID:
+					goto handle_JrnID
+
+				case ffjtEventODay:
+					goto handle_ODay
+
+				case ffjtEventDirection:
+					goto handle_Direction
+
+				case ffjtEventVehID:
+					goto handle_VehID
+
+				case ffjtEventTimestamp:
+					goto handle_Timestamp
+
+				case ffjtEventTst:
+					goto handle_Tst
+
+				case ffjtEventLat:
+					goto handle_Lat
+
+				case ffjtEventLng:
+					goto handle_Lng
+
+				case ffjtEventHeading:
+					goto handle_Heading
+
+				case ffjtEventStart:
+					goto handle_Start
+
+				case ffjtEventDeltaToSchedule:
+					goto handle_DeltaToSchedule
+
+				case ffjtEventSpd:
+					goto handle_Spd
+
+				case ffjtEventAcc:
+					goto handle_Acc
+
+				case ffjtEventRouteID:
+					goto handle_RouteID
+
+				case ffjtEventStop:
+					goto handle_Stop
+
+				case ffjtEventOdometer:
+					goto handle_Odometer
+
+				case ffjtEventOccupancy:
+					goto handle_Occupancy
+
+				case ffjtEventnosuchkey:
+					err = fs.SkipField(tok)
+					if err != nil {
+						return fs.WrapErr(err)
+					}
+					state = fflib.FFParse_after_value
+					goto mainparse
+				}
+			} else {
+				goto wantedvalue
+			}
+		}
+	}
+
+handle_JrnID:
+
+	/* handler: j.JrnID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.JrnID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_ODay:
+
+	/* handler: j.ODay type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.ODay = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Direction:
+
+	/* handler: j.Direction type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Direction = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_VehID:
+
+	/* handler: j.VehID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.VehID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Timestamp:
+
+	/* handler: j.Timestamp type=int64 kind=int64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Timestamp = int64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Tst:
+
+	/* handler: j.Tst type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Tst = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Lat:
+
+	/* handler: j.Lat type=float64 kind=float64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Lat = float64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Lng:
+
+	/* handler: j.Lng type=float64 kind=float64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Lng = float64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Heading:
+
+	/* handler: j.Heading type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Heading = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Start:
+
+	/* handler: j.Start type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Start = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_DeltaToSchedule:
+
+	/* handler: j.DeltaToSchedule type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.DeltaToSchedule = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Spd:
+
+	/* handler: j.Spd type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Spd = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Acc:
+
+	/* handler: j.Acc type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Acc = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_RouteID:
+
+	/* handler: j.RouteID type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.RouteID = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Stop:
+
+	/* handler: j.Stop type=hsldatabridge.OptInt kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tbuf, err := fs.CaptureField(tok)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			err = j.Stop.UnmarshalJSON(tbuf)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Odometer:
+
+	/* handler: j.Odometer type=hsldatabridge.OptInt kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tbuf, err := fs.CaptureField(tok)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			err = j.Odometer.UnmarshalJSON(tbuf)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Occupancy:
+
+	/* handler: j.Occupancy type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Occupancy = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+wantedvalue:
+	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+wrongtokenerror:
+	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
+tokerror:
+	if fs.BigError != nil {
+		return fs.WrapErr(fs.BigError)
+	}
+	err = fs.Error.ToError()
+	if err != nil {
+		return fs.WrapErr(err)
+	}
+	panic("ffjson-generated: unreachable, please report bug.")
+done:
+
+	return nil
+}
+
+// MarshalJSON marshal bytes to json - template
+func (j *EventHolder) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if j == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := j.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSONBuf marshal buff to json - template
+func (j *EventHolder) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if j == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{ `)
+	if j.VP != nil {
+		if true {
+			buf.WriteString(`"VP":`)
+
+			{
+
+				err = j.VP.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.DUE != nil {
+		if true {
+			buf.WriteString(`"DUE":`)
+
+			{
+
+				err = j.DUE.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.ARR != nil {
+		if true {
+			buf.WriteString(`"ARR":`)
+
+			{
+
+				err = j.ARR.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.DEP != nil {
+		if true {
+			buf.WriteString(`"DEP":`)
+
+			{
+
+				err = j.DEP.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.ARS != nil {
+		if true {
+			buf.WriteString(`"ARS":`)
+
+			{
+
+				err = j.ARS.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.PDE != nil {
+		if true {
+			buf.WriteString(`"PDE":`)
+
+			{
+
+				err = j.PDE.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.PAS != nil {
+		if true {
+			buf.WriteString(`"PAS":`)
+
+			{
+
+				err = j.PAS.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.WAIT != nil {
+		if true {
+			buf.WriteString(`"WAIT":`)
+
+			{
+
+				err = j.WAIT.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.DOO != nil {
+		if true {
+			buf.WriteString(`"DOO":`)
+
+			{
+
+				err = j.DOO.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.DOC != nil {
+		if true {
+			buf.WriteString(`"DOC":`)
+
+			{
+
+				err = j.DOC.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.TLR != nil {
+		if true {
+			buf.WriteString(`"TLR":`)
+
+			{
+
+				err = j.TLR.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.TLA != nil {
+		if true {
+			buf.WriteString(`"TLA":`)
+
+			{
+
+				err = j.TLA.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.DA != nil {
+		if true {
+			buf.WriteString(`"DA":`)
+
+			{
+
+				err = j.DA.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.DOUT != nil {
+		if true {
+			buf.WriteString(`"DOUT":`)
+
+			{
+
+				err = j.DOUT.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.BA != nil {
+		if true {
+			buf.WriteString(`"BA":`)
+
+			{
+
+				err = j.BA.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.BOUT != nil {
+		if true {
+			buf.WriteString(`"BOUT":`)
+
+			{
+
+				err = j.BOUT.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.VJA != nil {
+		if true {
+			buf.WriteString(`"VJA":`)
+
+			{
+
+				err = j.VJA.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.VJOUT != nil {
+		if true {
+			buf.WriteString(`"VJOUT":`)
+
+			{
+
+				err = j.VJOUT.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	if j.Topic != nil {
+		if true {
+			buf.WriteString(`"topic":`)
+
+			{
+
+				err = j.Topic.MarshalJSONBuf(buf)
+				if err != nil {
+					return err
+				}
+
+			}
+			buf.WriteByte(',')
+		}
+	}
+	buf.Rewind(1)
+	buf.WriteByte('}')
+	return nil
+}
+
+const (
+	ffjtEventHolderbase = iota
+	ffjtEventHoldernosuchkey
+
+	ffjtEventHolderVP
+
+	ffjtEventHolderDUE
+
+	ffjtEventHolderARR
+
+	ffjtEventHolderDEP
+
+	ffjtEventHolderARS
+
+	ffjtEventHolderPDE
+
+	ffjtEventHolderPAS
+
+	ffjtEventHolderWAIT
+
+	ffjtEventHolderDOO
+
+	ffjtEventHolderDOC
+
+	ffjtEventHolderTLR
+
+	ffjtEventHolderTLA
+
+	ffjtEventHolderDA
+
+	ffjtEventHolderDOUT
+
+	ffjtEventHolderBA
+
+	ffjtEventHolderBOUT
+
+	ffjtEventHolderVJA
+
+	ffjtEventHolderVJOUT
+
+	ffjtEventHolderTopic
+)
+
+var ffjKeyEventHolderVP = []byte("VP")
+
+var ffjKeyEventHolderDUE = []byte("DUE")
+
+var ffjKeyEventHolderARR = []byte("ARR")
+
+var ffjKeyEventHolderDEP = []byte("DEP")
+
+var ffjKeyEventHolderARS = []byte("ARS")
+
+var ffjKeyEventHolderPDE = []byte("PDE")
+
+var ffjKeyEventHolderPAS = []byte("PAS")
+
+var ffjKeyEventHolderWAIT = []byte("WAIT")
+
+var ffjKeyEventHolderDOO = []byte("DOO")
+
+var ffjKeyEventHolderDOC = []byte("DOC")
+
+var ffjKeyEventHolderTLR = []byte("TLR")
+
+var ffjKeyEventHolderTLA = []byte("TLA")
+
+var ffjKeyEventHolderDA = []byte("DA")
+
+var ffjKeyEventHolderDOUT = []byte("DOUT")
+
+var ffjKeyEventHolderBA = []byte("BA")
+
+var ffjKeyEventHolderBOUT = []byte("BOUT")
+
+var ffjKeyEventHolderVJA = []byte("VJA")
+
+var ffjKeyEventHolderVJOUT = []byte("VJOUT")
+
+var ffjKeyEventHolderTopic = []byte("topic")
+
+// UnmarshalJSON umarshall json - template of ffjson
+func (j *EventHolder) UnmarshalJSON(input []byte) error {
+	fs := fflib.NewFFLexer(input)
+	return j.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
+}
+
+// UnmarshalJSONFFLexer fast json unmarshall - template ffjson
+func (j *EventHolder) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+	var err error
+	currentKey := ffjtEventHolderbase
+	_ = currentKey
+	tok := fflib.FFTok_init
+	wantedTok := fflib.FFTok_init
+
+mainparse:
+	for {
+		tok = fs.Scan()
+		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
+		if tok == fflib.FFTok_error {
+			goto tokerror
+		}
+
+		switch state {
+
+		case fflib.FFParse_map_start:
+			if tok != fflib.FFTok_left_bracket {
+				wantedTok = fflib.FFTok_left_bracket
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_key
+			continue
+
+		case fflib.FFParse_after_value:
+			if tok == fflib.FFTok_comma {
+				state = fflib.FFParse_want_key
+			} else if tok == fflib.FFTok_right_bracket {
+				goto done
+			} else {
+				wantedTok = fflib.FFTok_comma
+				goto wrongtokenerror
+			}
+
+		case fflib.FFParse_want_key:
+			// json {} ended. goto exit. woo.
+			if tok == fflib.FFTok_right_bracket {
+				goto done
+			}
+			if tok != fflib.FFTok_string {
+				wantedTok = fflib.FFTok_string
+				goto wrongtokenerror
+			}
+
+			kn := fs.Output.Bytes()
+			if len(kn) <= 0 {
+				// "" case. hrm.
+				currentKey = ffjtEventHoldernosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			} else {
+				switch kn[0] {
+
+				case 'A':
+
+					if bytes.Equal(ffjKeyEventHolderARR, kn) {
+						currentKey = ffjtEventHolderARR
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventHolderARS, kn) {
+						currentKey = ffjtEventHolderARS
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'B':
+
+					if bytes.Equal(ffjKeyEventHolderBA, kn) {
+						currentKey = ffjtEventHolderBA
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventHolderBOUT, kn) {
+						currentKey = ffjtEventHolderBOUT
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'D':
+
+					if bytes.Equal(ffjKeyEventHolderDUE, kn) {
+						currentKey = ffjtEventHolderDUE
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventHolderDEP, kn) {
+						currentKey = ffjtEventHolderDEP
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventHolderDOO, kn) {
+						currentKey = ffjtEventHolderDOO
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventHolderDOC, kn) {
+						currentKey = ffjtEventHolderDOC
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventHolderDA, kn) {
+						currentKey = ffjtEventHolderDA
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventHolderDOUT, kn) {
+						currentKey = ffjtEventHolderDOUT
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'P':
+
+					if bytes.Equal(ffjKeyEventHolderPDE, kn) {
+						currentKey = ffjtEventHolderPDE
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventHolderPAS, kn) {
+						currentKey = ffjtEventHolderPAS
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'T':
+
+					if bytes.Equal(ffjKeyEventHolderTLR, kn) {
+						currentKey = ffjtEventHolderTLR
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventHolderTLA, kn) {
+						currentKey = ffjtEventHolderTLA
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'V':
+
+					if bytes.Equal(ffjKeyEventHolderVP, kn) {
+						currentKey = ffjtEventHolderVP
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventHolderVJA, kn) {
+						currentKey = ffjtEventHolderVJA
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyEventHolderVJOUT, kn) {
+						currentKey = ffjtEventHolderVJOUT
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'W':
+
+					if bytes.Equal(ffjKeyEventHolderWAIT, kn) {
+						currentKey = ffjtEventHolderWAIT
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 't':
+
+					if bytes.Equal(ffjKeyEventHolderTopic, kn) {
+						currentKey = ffjtEventHolderTopic
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderTopic, kn) {
+					currentKey = ffjtEventHolderTopic
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderVJOUT, kn) {
+					currentKey = ffjtEventHolderVJOUT
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderVJA, kn) {
+					currentKey = ffjtEventHolderVJA
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderBOUT, kn) {
+					currentKey = ffjtEventHolderBOUT
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderBA, kn) {
+					currentKey = ffjtEventHolderBA
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderDOUT, kn) {
+					currentKey = ffjtEventHolderDOUT
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderDA, kn) {
+					currentKey = ffjtEventHolderDA
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderTLA, kn) {
+					currentKey = ffjtEventHolderTLA
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderTLR, kn) {
+					currentKey = ffjtEventHolderTLR
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderDOC, kn) {
+					currentKey = ffjtEventHolderDOC
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderDOO, kn) {
+					currentKey = ffjtEventHolderDOO
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderWAIT, kn) {
+					currentKey = ffjtEventHolderWAIT
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyEventHolderPAS, kn) {
+					currentKey = ffjtEventHolderPAS
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderPDE, kn) {
+					currentKey = ffjtEventHolderPDE
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyEventHolderARS, kn) {
+					currentKey = ffjtEventHolderARS
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderDEP, kn) {
+					currentKey = ffjtEventHolderDEP
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderARR, kn) {
+					currentKey = ffjtEventHolderARR
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderDUE, kn) {
+					currentKey = ffjtEventHolderDUE
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyEventHolderVP, kn) {
+					currentKey = ffjtEventHolderVP
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffjtEventHoldernosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			}
+
+		case fflib.FFParse_want_colon:
+			if tok != fflib.FFTok_colon {
+				wantedTok = fflib.FFTok_colon
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_value
+			continue
+		case fflib.FFParse_want_value:
+
+			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
+				switch currentKey {
+
+				case ffjtEventHolderVP:
+					goto handle_VP
+
+				case ffjtEventHolderDUE:
+					goto handle_DUE
+
+				case ffjtEventHolderARR:
+					goto handle_ARR
+
+				case ffjtEventHolderDEP:
+					goto handle_DEP
+
+				case ffjtEventHolderARS:
+					goto handle_ARS
+
+				case ffjtEventHolderPDE:
+					goto handle_PDE
+
+				case ffjtEventHolderPAS:
+					goto handle_PAS
+
+				case ffjtEventHolderWAIT:
+					goto handle_WAIT
+
+				case ffjtEventHolderDOO:
+					goto handle_DOO
+
+				case ffjtEventHolderDOC:
+					goto handle_DOC
+
+				case ffjtEventHolderTLR:
+					goto handle_TLR
+
+				case ffjtEventHolderTLA:
+					goto handle_TLA
+
+				case ffjtEventHolderDA:
+					goto handle_DA
+
+				case ffjtEventHolderDOUT:
+					goto handle_DOUT
+
+				case ffjtEventHolderBA:
+					goto handle_BA
+
+				case ffjtEventHolderBOUT:
+					goto handle_BOUT
+
+				case ffjtEventHolderVJA:
+					goto handle_VJA
+
+				case ffjtEventHolderVJOUT:
+					goto handle_VJOUT
+
+				case ffjtEventHolderTopic:
+					goto handle_Topic
+
+				case ffjtEventHoldernosuchkey:
+					err = fs.SkipField(tok)
+					if err != nil {
+						return fs.WrapErr(err)
+					}
+					state = fflib.FFParse_after_value
+					goto mainparse
+				}
+			} else {
+				goto wantedvalue
+			}
+		}
+	}
+
+handle_VP:
+
+	/* handler: j.VP type=hsldatabridge.Event kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.VP = nil
+
+		} else {
+
+			if j.VP == nil {
+				j.VP = new(Event)
+			}
+
+			err = j.VP.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_DUE:
+
+	/* handler: j.DUE type=hsldatabridge.StopEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.DUE = nil
+
+		} else {
+
+			if j.DUE == nil {
+				j.DUE = new(StopEvent)
+			}
+
+			err = j.DUE.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_ARR:
+
+	/* handler: j.ARR type=hsldatabridge.StopEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.ARR = nil
+
+		} else {
+
+			if j.ARR == nil {
+				j.ARR = new(StopEvent)
+			}
+
+			err = j.ARR.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_DEP:
+
+	/* handler: j.DEP type=hsldatabridge.StopEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.DEP = nil
+
+		} else {
+
+			if j.DEP == nil {
+				j.DEP = new(StopEvent)
+			}
+
+			err = j.DEP.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_ARS:
+
+	/* handler: j.ARS type=hsldatabridge.StopEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.ARS = nil
+
+		} else {
+
+			if j.ARS == nil {
+				j.ARS = new(StopEvent)
+			}
+
+			err = j.ARS.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_PDE:
+
+	/* handler: j.PDE type=hsldatabridge.StopEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.PDE = nil
+
+		} else {
+
+			if j.PDE == nil {
+				j.PDE = new(StopEvent)
+			}
+
+			err = j.PDE.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_PAS:
+
+	/* handler: j.PAS type=hsldatabridge.StopEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.PAS = nil
+
+		} else {
+
+			if j.PAS == nil {
+				j.PAS = new(StopEvent)
+			}
+
+			err = j.PAS.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_WAIT:
+
+	/* handler: j.WAIT type=hsldatabridge.StopEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.WAIT = nil
+
+		} else {
+
+			if j.WAIT == nil {
+				j.WAIT = new(StopEvent)
+			}
+
+			err = j.WAIT.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_DOO:
+
+	/* handler: j.DOO type=hsldatabridge.DoorEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.DOO = nil
+
+		} else {
+
+			if j.DOO == nil {
+				j.DOO = new(DoorEvent)
+			}
+
+			err = j.DOO.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_DOC:
+
+	/* handler: j.DOC type=hsldatabridge.DoorEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.DOC = nil
+
+		} else {
+
+			if j.DOC == nil {
+				j.DOC = new(DoorEvent)
+			}
+
+			err = j.DOC.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_TLR:
+
+	/* handler: j.TLR type=hsldatabridge.TrafficLightEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.TLR = nil
+
+		} else {
+
+			if j.TLR == nil {
+				j.TLR = new(TrafficLightEvent)
+			}
+
+			err = j.TLR.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_TLA:
+
+	/* handler: j.TLA type=hsldatabridge.TrafficLightEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.TLA = nil
+
+		} else {
+
+			if j.TLA == nil {
+				j.TLA = new(TrafficLightEvent)
+			}
+
+			err = j.TLA.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_DA:
+
+	/* handler: j.DA type=hsldatabridge.SignOnEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.DA = nil
+
+		} else {
+
+			if j.DA == nil {
+				j.DA = new(SignOnEvent)
+			}
+
+			err = j.DA.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_DOUT:
+
+	/* handler: j.DOUT type=hsldatabridge.SignOnEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.DOUT = nil
+
+		} else {
+
+			if j.DOUT == nil {
+				j.DOUT = new(SignOnEvent)
+			}
+
+			err = j.DOUT.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_BA:
+
+	/* handler: j.BA type=hsldatabridge.SignOnEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.BA = nil
+
+		} else {
+
+			if j.BA == nil {
+				j.BA = new(SignOnEvent)
+			}
+
+			err = j.BA.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_BOUT:
+
+	/* handler: j.BOUT type=hsldatabridge.SignOnEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.BOUT = nil
+
+		} else {
+
+			if j.BOUT == nil {
+				j.BOUT = new(SignOnEvent)
+			}
+
+			err = j.BOUT.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_VJA:
+
+	/* handler: j.VJA type=hsldatabridge.SignOnEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.VJA = nil
+
+		} else {
+
+			if j.VJA == nil {
+				j.VJA = new(SignOnEvent)
+			}
+
+			err = j.VJA.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_VJOUT:
+
+	/* handler: j.VJOUT type=hsldatabridge.SignOnEvent kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.VJOUT = nil
+
+		} else {
+
+			if j.VJOUT == nil {
+				j.VJOUT = new(SignOnEvent)
+			}
+
+			err = j.VJOUT.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Topic:
+
+	/* handler: j.Topic type=hsldatabridge.Topic kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+			j.Topic = nil
+
+		} else {
+
+			if j.Topic == nil {
+				j.Topic = new(Topic)
+			}
+
+			err = j.Topic.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+			if err != nil {
+				return err
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+wantedvalue:
+	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+wrongtokenerror:
+	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
+tokerror:
+	if fs.BigError != nil {
+		return fs.WrapErr(fs.BigError)
+	}
+	err = fs.Error.ToError()
+	if err != nil {
+		return fs.WrapErr(err)
+	}
+	panic("ffjson-generated: unreachable, please report bug.")
+done:
+
+	return nil
+}
+
+// MarshalJSON marshal bytes to json - template
+func (j *SignOnEvent) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if j == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := j.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSONBuf marshal buff to json - template
+func (j *SignOnEvent) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if j == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{"dr-type":`)
+	fflib.FormatBits2(buf, uint64(j.DriverType), 10, j.DriverType < 0)
+	buf.WriteString(`,"jrn":`)
+	fflib.FormatBits2(buf, uint64(j.JrnID), 10, j.JrnID < 0)
+	buf.WriteString(`,"oday":`)
+	fflib.WriteJsonString(buf, string(j.ODay))
+	buf.WriteString(`,"dir":`)
+	fflib.WriteJsonString(buf, string(j.Direction))
+	buf.WriteString(`,"veh":`)
+	fflib.FormatBits2(buf, uint64(j.VehID), 10, j.VehID < 0)
+	buf.WriteString(`,"tsi":`)
+	fflib.FormatBits2(buf, uint64(j.Timestamp), 10, j.Timestamp < 0)
+	buf.WriteString(`,"tst":`)
+	fflib.WriteJsonString(buf, string(j.Tst))
+	buf.WriteString(`,"lat":`)
+	fflib.AppendFloat(buf, float64(j.Lat), 'g', -1, 64)
+	buf.WriteString(`,"long":`)
+	fflib.AppendFloat(buf, float64(j.Lng), 'g', -1, 64)
+	buf.WriteString(`,"hdg":`)
+	fflib.FormatBits2(buf, uint64(j.Heading), 10, j.Heading < 0)
+	buf.WriteString(`,"start":`)
+	fflib.WriteJsonString(buf, string(j.Start))
+	buf.WriteString(`,"dl":`)
+	fflib.AppendFloat(buf, float64(j.DeltaToSchedule), 'g', -1, 32)
+	buf.WriteString(`,"spd":`)
+	fflib.AppendFloat(buf, float64(j.Spd), 'g', -1, 32)
+	buf.WriteString(`,"acc":`)
+	fflib.AppendFloat(buf, float64(j.Acc), 'g', -1, 32)
+	buf.WriteString(`,"route":`)
+	fflib.WriteJsonString(buf, string(j.RouteID))
+	buf.WriteString(`,"stop":`)
+
+	{
+
+		obj, err = j.Stop.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteString(`,"odo":`)
+
+	{
+
+		obj, err = j.Odometer.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteString(`,"occu":`)
+	fflib.FormatBits2(buf, uint64(j.Occupancy), 10, j.Occupancy < 0)
+	buf.WriteByte('}')
+	return nil
+}
+
+const (
+	ffjtSignOnEventbase = iota
+	ffjtSignOnEventnosuchkey
+
+	ffjtSignOnEventDriverType
+
+	ffjtSignOnEventJrnID
+
+	ffjtSignOnEventODay
+
+	ffjtSignOnEventDirection
+
+	ffjtSignOnEventVehID
+
+	ffjtSignOnEventTimestamp
+
+	ffjtSignOnEventTst
+
+	ffjtSignOnEventLat
+
+	ffjtSignOnEventLng
+
+	ffjtSignOnEventHeading
+
+	ffjtSignOnEventStart
+
+	ffjtSignOnEventDeltaToSchedule
+
+	ffjtSignOnEventSpd
+
+	ffjtSignOnEventAcc
+
+	ffjtSignOnEventRouteID
+
+	ffjtSignOnEventStop
+
+	ffjtSignOnEventOdometer
+
+	ffjtSignOnEventOccupancy
+)
+
+var ffjKeySignOnEventDriverType = []byte("dr-type")
+
+var ffjKeySignOnEventJrnID = []byte("jrn")
+
+var ffjKeySignOnEventODay = []byte("oday")
+
+var ffjKeySignOnEventDirection = []byte("dir")
+
+var ffjKeySignOnEventVehID = []byte("veh")
+
+var ffjKeySignOnEventTimestamp = []byte("tsi")
+
+var ffjKeySignOnEventTst = []byte("tst")
+
+var ffjKeySignOnEventLat = []byte("lat")
+
+var ffjKeySignOnEventLng = []byte("long")
+
+var ffjKeySignOnEventHeading = []byte("hdg")
+
+var ffjKeySignOnEventStart = []byte("start")
+
+var ffjKeySignOnEventDeltaToSchedule = []byte("dl")
+
+var ffjKeySignOnEventSpd = []byte("spd")
+
+var ffjKeySignOnEventAcc = []byte("acc")
+
+var ffjKeySignOnEventRouteID = []byte("route")
+
+var ffjKeySignOnEventStop = []byte("stop")
+
+var ffjKeySignOnEventOdometer = []byte("odo")
+
+var ffjKeySignOnEventOccupancy = []byte("occu")
+
+// UnmarshalJSON umarshall json - template of ffjson
+func (j *SignOnEvent) UnmarshalJSON(input []byte) error {
+	fs := fflib.NewFFLexer(input)
+	return j.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
+}
+
+// UnmarshalJSONFFLexer fast json unmarshall - template ffjson
+func (j *SignOnEvent) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+	var err error
+	currentKey := ffjtSignOnEventbase
+	_ = currentKey
+	tok := fflib.FFTok_init
+	wantedTok := fflib.FFTok_init
+
+mainparse:
+	for {
+		tok = fs.Scan()
+		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
+		if tok == fflib.FFTok_error {
+			goto tokerror
+		}
+
+		switch state {
+
+		case fflib.FFParse_map_start:
+			if tok != fflib.FFTok_left_bracket {
+				wantedTok = fflib.FFTok_left_bracket
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_key
+			continue
+
+		case fflib.FFParse_after_value:
+			if tok == fflib.FFTok_comma {
+				state = fflib.FFParse_want_key
+			} else if tok == fflib.FFTok_right_bracket {
+				goto done
+			} else {
+				wantedTok = fflib.FFTok_comma
+				goto wrongtokenerror
+			}
+
+		case fflib.FFParse_want_key:
+			// json {} ended. goto exit. woo.
+			if tok == fflib.FFTok_right_bracket {
+				goto done
+			}
+			if tok != fflib.FFTok_string {
+				wantedTok = fflib.FFTok_string
+				goto wrongtokenerror
+			}
+
+			kn := fs.Output.Bytes()
+			if len(kn) <= 0 {
+				// "" case. hrm.
+				currentKey = ffjtSignOnEventnosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			} else {
+				switch kn[0] {
+
+				case 'a':
+
+					if bytes.Equal(ffjKeySignOnEventAcc, kn) {
+						currentKey = ffjtSignOnEventAcc
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'd':
+
+					if bytes.Equal(ffjKeySignOnEventDriverType, kn) {
+						currentKey = ffjtSignOnEventDriverType
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeySignOnEventDirection, kn) {
+						currentKey = ffjtSignOnEventDirection
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeySignOnEventDeltaToSchedule, kn) {
+						currentKey = ffjtSignOnEventDeltaToSchedule
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'h':
+
+					if bytes.Equal(ffjKeySignOnEventHeading, kn) {
+						currentKey = ffjtSignOnEventHeading
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'j':
+
+					if bytes.Equal(ffjKeySignOnEventJrnID, kn) {
+						currentKey = ffjtSignOnEventJrnID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'l':
+
+					if bytes.Equal(ffjKeySignOnEventLat, kn) {
+						currentKey = ffjtSignOnEventLat
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeySignOnEventLng, kn) {
+						currentKey = ffjtSignOnEventLng
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'o':
+
+					if bytes.Equal(ffjKeySignOnEventODay, kn) {
+						currentKey = ffjtSignOnEventODay
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeySignOnEventOdometer, kn) {
+						currentKey = ffjtSignOnEventOdometer
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeySignOnEventOccupancy, kn) {
+						currentKey = ffjtSignOnEventOccupancy
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'r':
+
+					if bytes.Equal(ffjKeySignOnEventRouteID, kn) {
+						currentKey = ffjtSignOnEventRouteID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 's':
+
+					if bytes.Equal(ffjKeySignOnEventStart, kn) {
+						currentKey = ffjtSignOnEventStart
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeySignOnEventSpd, kn) {
+						currentKey = ffjtSignOnEventSpd
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeySignOnEventStop, kn) {
+						currentKey = ffjtSignOnEventStop
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 't':
+
+					if bytes.Equal(ffjKeySignOnEventTimestamp, kn) {
+						currentKey = ffjtSignOnEventTimestamp
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeySignOnEventTst, kn) {
+						currentKey = ffjtSignOnEventTst
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'v':
+
+					if bytes.Equal(ffjKeySignOnEventVehID, kn) {
+						currentKey = ffjtSignOnEventVehID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventOccupancy, kn) {
+					currentKey = ffjtSignOnEventOccupancy
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventOdometer, kn) {
+					currentKey = ffjtSignOnEventOdometer
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeySignOnEventStop, kn) {
+					currentKey = ffjtSignOnEventStop
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventRouteID, kn) {
+					currentKey = ffjtSignOnEventRouteID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventAcc, kn) {
+					currentKey = ffjtSignOnEventAcc
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeySignOnEventSpd, kn) {
+					currentKey = ffjtSignOnEventSpd
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventDeltaToSchedule, kn) {
+					currentKey = ffjtSignOnEventDeltaToSchedule
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeySignOnEventStart, kn) {
+					currentKey = ffjtSignOnEventStart
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventHeading, kn) {
+					currentKey = ffjtSignOnEventHeading
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventLng, kn) {
+					currentKey = ffjtSignOnEventLng
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventLat, kn) {
+					currentKey = ffjtSignOnEventLat
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeySignOnEventTst, kn) {
+					currentKey = ffjtSignOnEventTst
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeySignOnEventTimestamp, kn) {
+					currentKey = ffjtSignOnEventTimestamp
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventVehID, kn) {
+					currentKey = ffjtSignOnEventVehID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventDirection, kn) {
+					currentKey = ffjtSignOnEventDirection
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventODay, kn) {
+					currentKey = ffjtSignOnEventODay
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeySignOnEventJrnID, kn) {
+					currentKey = ffjtSignOnEventJrnID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffjKeySignOnEventDriverType, kn) {
+					currentKey = ffjtSignOnEventDriverType
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffjtSignOnEventnosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			}
+
+		case fflib.FFParse_want_colon:
+			if tok != fflib.FFTok_colon {
+				wantedTok = fflib.FFTok_colon
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_value
+			continue
+		case fflib.FFParse_want_value:
+
+			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
+				switch currentKey {
+
+				case ffjtSignOnEventDriverType:
+					goto handle_DriverType
+
+				case ffjtSignOnEventJrnID:
+					goto handle_JrnID
+
+				case ffjtSignOnEventODay:
+					goto handle_ODay
+
+				case ffjtSignOnEventDirection:
+					goto handle_Direction
+
+				case ffjtSignOnEventVehID:
+					goto handle_VehID
+
+				case ffjtSignOnEventTimestamp:
+					goto handle_Timestamp
+
+				case ffjtSignOnEventTst:
+					goto handle_Tst
+
+				case ffjtSignOnEventLat:
+					goto handle_Lat
+
+				case ffjtSignOnEventLng:
+					goto handle_Lng
+
+				case ffjtSignOnEventHeading:
+					goto handle_Heading
+
+				case ffjtSignOnEventStart:
+					goto handle_Start
+
+				case ffjtSignOnEventDeltaToSchedule:
+					goto handle_DeltaToSchedule
+
+				case ffjtSignOnEventSpd:
+					goto handle_Spd
+
+				case ffjtSignOnEventAcc:
+					goto handle_Acc
+
+				case ffjtSignOnEventRouteID:
+					goto handle_RouteID
+
+				case ffjtSignOnEventStop:
+					goto handle_Stop
+
+				case ffjtSignOnEventOdometer:
+					goto handle_Odometer
+
+				case ffjtSignOnEventOccupancy:
+					goto handle_Occupancy
+
+				case ffjtSignOnEventnosuchkey:
+					err = fs.SkipField(tok)
+					if err != nil {
+						return fs.WrapErr(err)
+					}
+					state = fflib.FFParse_after_value
+					goto mainparse
+				}
+			} else {
+				goto wantedvalue
+			}
+		}
+	}
+
+handle_DriverType:
+
+	/* handler: j.DriverType type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.DriverType = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_JrnID:
+
+	/* handler: j.JrnID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.JrnID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_ODay:
+
+	/* handler: j.ODay type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.ODay = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Direction:
+
+	/* handler: j.Direction type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Direction = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_VehID:
+
+	/* handler: j.VehID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.VehID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Timestamp:
+
+	/* handler: j.Timestamp type=int64 kind=int64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Timestamp = int64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Tst:
+
+	/* handler: j.Tst type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Tst = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Lat:
+
+	/* handler: j.Lat type=float64 kind=float64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Lat = float64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Lng:
+
+	/* handler: j.Lng type=float64 kind=float64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Lng = float64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Heading:
+
+	/* handler: j.Heading type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Heading = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Start:
+
+	/* handler: j.Start type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Start = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_DeltaToSchedule:
+
+	/* handler: j.DeltaToSchedule type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.DeltaToSchedule = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Spd:
+
+	/* handler: j.Spd type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Spd = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Acc:
+
+	/* handler: j.Acc type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Acc = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_RouteID:
+
+	/* handler: j.RouteID type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.RouteID = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Stop:
+
+	/* handler: j.Stop type=hsldatabridge.OptInt kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tbuf, err := fs.CaptureField(tok)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			err = j.Stop.UnmarshalJSON(tbuf)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Odometer:
+
+	/* handler: j.Odometer type=hsldatabridge.OptInt kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tbuf, err := fs.CaptureField(tok)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			err = j.Odometer.UnmarshalJSON(tbuf)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Occupancy:
+
+	/* handler: j.Occupancy type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Occupancy = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+wantedvalue:
+	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+wrongtokenerror:
+	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
+tokerror:
+	if fs.BigError != nil {
+		return fs.WrapErr(fs.BigError)
+	}
+	err = fs.Error.ToError()
+	if err != nil {
+		return fs.WrapErr(err)
+	}
+	panic("ffjson-generated: unreachable, please report bug.")
+done:
+
+	return nil
+}
+
+// MarshalJSON marshal bytes to json - template
+func (j *StopEvent) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if j == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := j.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSONBuf marshal buff to json - template
+func (j *StopEvent) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if j == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{"ttarr":`)
+	fflib.WriteJsonString(buf, string(j.ScheduledArrival))
+	buf.WriteString(`,"ttdep":`)
+	fflib.WriteJsonString(buf, string(j.ScheduledDeparture))
+	buf.WriteString(`,"jrn":`)
+	fflib.FormatBits2(buf, uint64(j.JrnID), 10, j.JrnID < 0)
+	buf.WriteString(`,"oday":`)
+	fflib.WriteJsonString(buf, string(j.ODay))
+	buf.WriteString(`,"dir":`)
+	fflib.WriteJsonString(buf, string(j.Direction))
+	buf.WriteString(`,"veh":`)
+	fflib.FormatBits2(buf, uint64(j.VehID), 10, j.VehID < 0)
+	buf.WriteString(`,"tsi":`)
+	fflib.FormatBits2(buf, uint64(j.Timestamp), 10, j.Timestamp < 0)
+	buf.WriteString(`,"tst":`)
+	fflib.WriteJsonString(buf, string(j.Tst))
+	buf.WriteString(`,"lat":`)
+	fflib.AppendFloat(buf, float64(j.Lat), 'g', -1, 64)
+	buf.WriteString(`,"long":`)
+	fflib.AppendFloat(buf, float64(j.Lng), 'g', -1, 64)
+	buf.WriteString(`,"hdg":`)
+	fflib.FormatBits2(buf, uint64(j.Heading), 10, j.Heading < 0)
+	buf.WriteString(`,"start":`)
+	fflib.WriteJsonString(buf, string(j.Start))
+	buf.WriteString(`,"dl":`)
+	fflib.AppendFloat(buf, float64(j.DeltaToSchedule), 'g', -1, 32)
+	buf.WriteString(`,"spd":`)
+	fflib.AppendFloat(buf, float64(j.Spd), 'g', -1, 32)
+	buf.WriteString(`,"acc":`)
+	fflib.AppendFloat(buf, float64(j.Acc), 'g', -1, 32)
+	buf.WriteString(`,"route":`)
+	fflib.WriteJsonString(buf, string(j.RouteID))
+	buf.WriteString(`,"stop":`)
+
+	{
+
+		obj, err = j.Stop.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteString(`,"odo":`)
+
+	{
+
+		obj, err = j.Odometer.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteString(`,"occu":`)
+	fflib.FormatBits2(buf, uint64(j.Occupancy), 10, j.Occupancy < 0)
+	buf.WriteByte('}')
+	return nil
+}
+
+const (
+	ffjtStopEventbase = iota
+	ffjtStopEventnosuchkey
+
+	ffjtStopEventScheduledArrival
+
+	ffjtStopEventScheduledDeparture
+
+	ffjtStopEventJrnID
+
+	ffjtStopEventODay
+
+	ffjtStopEventDirection
+
+	ffjtStopEventVehID
+
+	ffjtStopEventTimestamp
+
+	ffjtStopEventTst
+
+	ffjtStopEventLat
+
+	ffjtStopEventLng
+
+	ffjtStopEventHeading
+
+	ffjtStopEventStart
+
+	ffjtStopEventDeltaToSchedule
+
+	ffjtStopEventSpd
+
+	ffjtStopEventAcc
+
+	ffjtStopEventRouteID
+
+	ffjtStopEventStop
+
+	ffjtStopEventOdometer
+
+	ffjtStopEventOccupancy
+)
+
+var ffjKeyStopEventScheduledArrival = []byte("ttarr")
+
+var ffjKeyStopEventScheduledDeparture = []byte("ttdep")
+
+var ffjKeyStopEventJrnID = []byte("jrn")
+
+var ffjKeyStopEventODay = []byte("oday")
+
+var ffjKeyStopEventDirection = []byte("dir")
+
+var ffjKeyStopEventVehID = []byte("veh")
+
+var ffjKeyStopEventTimestamp = []byte("tsi")
+
+var ffjKeyStopEventTst = []byte("tst")
+
+var ffjKeyStopEventLat = []byte("lat")
+
+var ffjKeyStopEventLng = []byte("long")
+
+var ffjKeyStopEventHeading = []byte("hdg")
+
+var ffjKeyStopEventStart = []byte("start")
+
+var ffjKeyStopEventDeltaToSchedule = []byte("dl")
+
+var ffjKeyStopEventSpd = []byte("spd")
+
+var ffjKeyStopEventAcc = []byte("acc")
+
+var ffjKeyStopEventRouteID = []byte("route")
+
+var ffjKeyStopEventStop = []byte("stop")
+
+var ffjKeyStopEventOdometer = []byte("odo")
+
+var ffjKeyStopEventOccupancy = []byte("occu")
+
+// UnmarshalJSON umarshall json - template of ffjson
+func (j *StopEvent) UnmarshalJSON(input []byte) error {
+	fs := fflib.NewFFLexer(input)
+	return j.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
+}
+
+// UnmarshalJSONFFLexer fast json unmarshall - template ffjson
+func (j *StopEvent) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+	var err error
+	currentKey := ffjtStopEventbase
+	_ = currentKey
+	tok := fflib.FFTok_init
+	wantedTok := fflib.FFTok_init
+
+mainparse:
+	for {
+		tok = fs.Scan()
+		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
+		if tok == fflib.FFTok_error {
+			goto tokerror
+		}
+
+		switch state {
+
+		case fflib.FFParse_map_start:
+			if tok != fflib.FFTok_left_bracket {
+				wantedTok = fflib.FFTok_left_bracket
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_key
+			continue
+
+		case fflib.FFParse_after_value:
+			if tok == fflib.FFTok_comma {
+				state = fflib.FFParse_want_key
+			} else if tok == fflib.FFTok_right_bracket {
+				goto done
+			} else {
+				wantedTok = fflib.FFTok_comma
+				goto wrongtokenerror
+			}
+
+		case fflib.FFParse_want_key:
+			// json {} ended. goto exit. woo.
+			if tok == fflib.FFTok_right_bracket {
+				goto done
+			}
+			if tok != fflib.FFTok_string {
+				wantedTok = fflib.FFTok_string
+				goto wrongtokenerror
+			}
+
+			kn := fs.Output.Bytes()
+			if len(kn) <= 0 {
+				// "" case. hrm.
+				currentKey = ffjtStopEventnosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			} else {
+				switch kn[0] {
+
+				case 'a':
+
+					if bytes.Equal(ffjKeyStopEventAcc, kn) {
+						currentKey = ffjtStopEventAcc
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'd':
+
+					if bytes.Equal(ffjKeyStopEventDirection, kn) {
+						currentKey = ffjtStopEventDirection
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyStopEventDeltaToSchedule, kn) {
+						currentKey = ffjtStopEventDeltaToSchedule
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'h':
+
+					if bytes.Equal(ffjKeyStopEventHeading, kn) {
+						currentKey = ffjtStopEventHeading
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'j':
+
+					if bytes.Equal(ffjKeyStopEventJrnID, kn) {
+						currentKey = ffjtStopEventJrnID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'l':
+
+					if bytes.Equal(ffjKeyStopEventLat, kn) {
+						currentKey = ffjtStopEventLat
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyStopEventLng, kn) {
+						currentKey = ffjtStopEventLng
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'o':
+
+					if bytes.Equal(ffjKeyStopEventODay, kn) {
+						currentKey = ffjtStopEventODay
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyStopEventOdometer, kn) {
+						currentKey = ffjtStopEventOdometer
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyStopEventOccupancy, kn) {
+						currentKey = ffjtStopEventOccupancy
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'r':
+
+					if bytes.Equal(ffjKeyStopEventRouteID, kn) {
+						currentKey = ffjtStopEventRouteID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 's':
+
+					if bytes.Equal(ffjKeyStopEventStart, kn) {
+						currentKey = ffjtStopEventStart
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyStopEventSpd, kn) {
+						currentKey = ffjtStopEventSpd
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyStopEventStop, kn) {
+						currentKey = ffjtStopEventStop
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 't':
+
+					if bytes.Equal(ffjKeyStopEventScheduledArrival, kn) {
+						currentKey = ffjtStopEventScheduledArrival
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyStopEventScheduledDeparture, kn) {
+						currentKey = ffjtStopEventScheduledDeparture
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyStopEventTimestamp, kn) {
+						currentKey = ffjtStopEventTimestamp
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyStopEventTst, kn) {
+						currentKey = ffjtStopEventTst
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'v':
+
+					if bytes.Equal(ffjKeyStopEventVehID, kn) {
+						currentKey = ffjtStopEventVehID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventOccupancy, kn) {
+					currentKey = ffjtStopEventOccupancy
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventOdometer, kn) {
+					currentKey = ffjtStopEventOdometer
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyStopEventStop, kn) {
+					currentKey = ffjtStopEventStop
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventRouteID, kn) {
+					currentKey = ffjtStopEventRouteID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventAcc, kn) {
+					currentKey = ffjtStopEventAcc
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyStopEventSpd, kn) {
+					currentKey = ffjtStopEventSpd
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventDeltaToSchedule, kn) {
+					currentKey = ffjtStopEventDeltaToSchedule
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyStopEventStart, kn) {
+					currentKey = ffjtStopEventStart
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventHeading, kn) {
+					currentKey = ffjtStopEventHeading
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventLng, kn) {
+					currentKey = ffjtStopEventLng
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventLat, kn) {
+					currentKey = ffjtStopEventLat
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyStopEventTst, kn) {
+					currentKey = ffjtStopEventTst
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyStopEventTimestamp, kn) {
+					currentKey = ffjtStopEventTimestamp
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventVehID, kn) {
+					currentKey = ffjtStopEventVehID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventDirection, kn) {
+					currentKey = ffjtStopEventDirection
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventODay, kn) {
+					currentKey = ffjtStopEventODay
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventJrnID, kn) {
+					currentKey = ffjtStopEventJrnID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventScheduledDeparture, kn) {
+					currentKey = ffjtStopEventScheduledDeparture
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyStopEventScheduledArrival, kn) {
+					currentKey = ffjtStopEventScheduledArrival
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffjtStopEventnosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			}
+
+		case fflib.FFParse_want_colon:
+			if tok != fflib.FFTok_colon {
+				wantedTok = fflib.FFTok_colon
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_value
+			continue
+		case fflib.FFParse_want_value:
+
+			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
+				switch currentKey {
+
+				case ffjtStopEventScheduledArrival:
+					goto handle_ScheduledArrival
+
+				case ffjtStopEventScheduledDeparture:
+					goto handle_ScheduledDeparture
+
+				case ffjtStopEventJrnID:
+					goto handle_JrnID
+
+				case ffjtStopEventODay:
+					goto handle_ODay
+
+				case ffjtStopEventDirection:
+					goto handle_Direction
+
+				case ffjtStopEventVehID:
+					goto handle_VehID
+
+				case ffjtStopEventTimestamp:
+					goto handle_Timestamp
+
+				case ffjtStopEventTst:
+					goto handle_Tst
+
+				case ffjtStopEventLat:
+					goto handle_Lat
+
+				case ffjtStopEventLng:
+					goto handle_Lng
+
+				case ffjtStopEventHeading:
+					goto handle_Heading
+
+				case ffjtStopEventStart:
+					goto handle_Start
+
+				case ffjtStopEventDeltaToSchedule:
+					goto handle_DeltaToSchedule
+
+				case ffjtStopEventSpd:
+					goto handle_Spd
+
+				case ffjtStopEventAcc:
+					goto handle_Acc
+
+				case ffjtStopEventRouteID:
+					goto handle_RouteID
+
+				case ffjtStopEventStop:
+					goto handle_Stop
+
+				case ffjtStopEventOdometer:
+					goto handle_Odometer
+
+				case ffjtStopEventOccupancy:
+					goto handle_Occupancy
+
+				case ffjtStopEventnosuchkey:
+					err = fs.SkipField(tok)
+					if err != nil {
+						return fs.WrapErr(err)
+					}
+					state = fflib.FFParse_after_value
+					goto mainparse
+				}
+			} else {
+				goto wantedvalue
+			}
+		}
+	}
+
+handle_ScheduledArrival:
+
+	/* handler: j.ScheduledArrival type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.ScheduledArrival = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_ScheduledDeparture:
+
+	/* handler: j.ScheduledDeparture type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.ScheduledDeparture = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_JrnID:
+
+	/* handler: j.JrnID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.JrnID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_ODay:
+
+	/* handler: j.ODay type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.ODay = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Direction:
+
+	/* handler: j.Direction type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Direction = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_VehID:
+
+	/* handler: j.VehID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.VehID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Timestamp:
+
+	/* handler: j.Timestamp type=int64 kind=int64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Timestamp = int64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Tst:
+
+	/* handler: j.Tst type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Tst = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Lat:
+
+	/* handler: j.Lat type=float64 kind=float64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Lat = float64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Lng:
+
+	/* handler: j.Lng type=float64 kind=float64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Lng = float64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Heading:
+
+	/* handler: j.Heading type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Heading = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Start:
+
+	/* handler: j.Start type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Start = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_DeltaToSchedule:
+
+	/* handler: j.DeltaToSchedule type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.DeltaToSchedule = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Spd:
+
+	/* handler: j.Spd type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Spd = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Acc:
+
+	/* handler: j.Acc type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Acc = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_RouteID:
+
+	/* handler: j.RouteID type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.RouteID = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Stop:
+
+	/* handler: j.Stop type=hsldatabridge.OptInt kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tbuf, err := fs.CaptureField(tok)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			err = j.Stop.UnmarshalJSON(tbuf)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Odometer:
+
+	/* handler: j.Odometer type=hsldatabridge.OptInt kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tbuf, err := fs.CaptureField(tok)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			err = j.Odometer.UnmarshalJSON(tbuf)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Occupancy:
+
+	/* handler: j.Occupancy type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Occupancy = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+wantedvalue:
+	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+wrongtokenerror:
+	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
+tokerror:
+	if fs.BigError != nil {
+		return fs.WrapErr(fs.BigError)
+	}
+	err = fs.Error.ToError()
+	if err != nil {
+		return fs.WrapErr(err)
+	}
+	panic("ffjson-generated: unreachable, please report bug.")
+done:
+
+	return nil
+}
+
+// MarshalJSON marshal bytes to json - template
+func (j *Topic) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if j == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := j.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSONBuf marshal buff to json - template
+func (j *Topic) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if j == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{"journey_type":`)
+	fflib.WriteJsonString(buf, string(j.JourneyType))
+	buf.WriteString(`,"temporal_type":`)
+	fflib.WriteJsonString(buf, string(j.TemporalType))
+	buf.WriteString(`,"event_type":`)
+	fflib.WriteJsonString(buf, string(j.EventType))
+	buf.WriteString(`,"mode":`)
+	fflib.WriteJsonString(buf, string(j.TransportMode))
+	buf.WriteString(`,"oper":`)
+	fflib.WriteJsonString(buf, string(j.OperatorID))
+	buf.WriteString(`,"veh":`)
+	fflib.WriteJsonString(buf, string(j.VehicleNumber))
+	buf.WriteString(`,"route":`)
+	fflib.WriteJsonString(buf, string(j.RouteID))
+	buf.WriteString(`,"dir":`)
+	fflib.WriteJsonString(buf, string(j.DirectionID))
+	buf.WriteString(`,"headsign":`)
+	fflib.WriteJsonString(buf, string(j.Headsign))
+	buf.WriteString(`,"start":`)
+	fflib.WriteJsonString(buf, string(j.StartTime))
+	buf.WriteString(`,"next_stop":`)
+	fflib.WriteJsonString(buf, string(j.NextStop))
+	buf.WriteString(`,"gh_level":`)
+	fflib.FormatBits2(buf, uint64(j.GeohashLevel), 10, j.GeohashLevel < 0)
+	buf.WriteString(`,"gh":`)
+	fflib.WriteJsonString(buf, string(j.Geohash))
+	buf.WriteByte('}')
+	return nil
+}
+
+const (
+	ffjtTopicbase = iota
+	ffjtTopicnosuchkey
+
+	ffjtTopicJourneyType
+
+	ffjtTopicTemporalType
+
+	ffjtTopicEventType
+
+	ffjtTopicTransportMode
+
+	ffjtTopicOperatorID
+
+	ffjtTopicVehicleNumber
+
+	ffjtTopicRouteID
+
+	ffjtTopicDirectionID
+
+	ffjtTopicHeadsign
+
+	ffjtTopicStartTime
+
+	ffjtTopicNextStop
+
+	ffjtTopicGeohashLevel
+
+	ffjtTopicGeohash
+)
+
+var ffjKeyTopicJourneyType = []byte("journey_type")
+
+var ffjKeyTopicTemporalType = []byte("temporal_type")
+
+var ffjKeyTopicEventType = []byte("event_type")
+
+var ffjKeyTopicTransportMode = []byte("mode")
+
+var ffjKeyTopicOperatorID = []byte("oper")
+
+var ffjKeyTopicVehicleNumber = []byte("veh")
+
+var ffjKeyTopicRouteID = []byte("route")
+
+var ffjKeyTopicDirectionID = []byte("dir")
+
+var ffjKeyTopicHeadsign = []byte("headsign")
+
+var ffjKeyTopicStartTime = []byte("start")
+
+var ffjKeyTopicNextStop = []byte("next_stop")
+
+var ffjKeyTopicGeohashLevel = []byte("gh_level")
+
+var ffjKeyTopicGeohash = []byte("gh")
+
+// UnmarshalJSON umarshall json - template of ffjson
+func (j *Topic) UnmarshalJSON(input []byte) error {
+	fs := fflib.NewFFLexer(input)
+	return j.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
+}
+
+// UnmarshalJSONFFLexer fast json unmarshall - template ffjson
+func (j *Topic) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+	var err error
+	currentKey := ffjtTopicbase
+	_ = currentKey
+	tok := fflib.FFTok_init
+	wantedTok := fflib.FFTok_init
+
+mainparse:
+	for {
+		tok = fs.Scan()
+		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
+		if tok == fflib.FFTok_error {
+			goto tokerror
+		}
+
+		switch state {
+
+		case fflib.FFParse_map_start:
+			if tok != fflib.FFTok_left_bracket {
+				wantedTok = fflib.FFTok_left_bracket
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_key
+			continue
+
+		case fflib.FFParse_after_value:
+			if tok == fflib.FFTok_comma {
+				state = fflib.FFParse_want_key
+			} else if tok == fflib.FFTok_right_bracket {
+				goto done
+			} else {
+				wantedTok = fflib.FFTok_comma
+				goto wrongtokenerror
+			}
+
+		case fflib.FFParse_want_key:
+			// json {} ended. goto exit. woo.
+			if tok == fflib.FFTok_right_bracket {
+				goto done
+			}
+			if tok != fflib.FFTok_string {
+				wantedTok = fflib.FFTok_string
+				goto wrongtokenerror
+			}
+
+			kn := fs.Output.Bytes()
+			if len(kn) <= 0 {
+				// "" case. hrm.
+				currentKey = ffjtTopicnosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			} else {
+				switch kn[0] {
+
+				case 'd':
+
+					if bytes.Equal(ffjKeyTopicDirectionID, kn) {
+						currentKey = ffjtTopicDirectionID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'e':
+
+					if bytes.Equal(ffjKeyTopicEventType, kn) {
+						currentKey = ffjtTopicEventType
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'g':
+
+					if bytes.Equal(ffjKeyTopicGeohashLevel, kn) {
+						currentKey = ffjtTopicGeohashLevel
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTopicGeohash, kn) {
+						currentKey = ffjtTopicGeohash
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'h':
+
+					if bytes.Equal(ffjKeyTopicHeadsign, kn) {
+						currentKey = ffjtTopicHeadsign
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'j':
+
+					if bytes.Equal(ffjKeyTopicJourneyType, kn) {
+						currentKey = ffjtTopicJourneyType
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'm':
+
+					if bytes.Equal(ffjKeyTopicTransportMode, kn) {
+						currentKey = ffjtTopicTransportMode
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'n':
+
+					if bytes.Equal(ffjKeyTopicNextStop, kn) {
+						currentKey = ffjtTopicNextStop
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'o':
+
+					if bytes.Equal(ffjKeyTopicOperatorID, kn) {
+						currentKey = ffjtTopicOperatorID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'r':
+
+					if bytes.Equal(ffjKeyTopicRouteID, kn) {
+						currentKey = ffjtTopicRouteID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 's':
+
+					if bytes.Equal(ffjKeyTopicStartTime, kn) {
+						currentKey = ffjtTopicStartTime
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 't':
+
+					if bytes.Equal(ffjKeyTopicTemporalType, kn) {
+						currentKey = ffjtTopicTemporalType
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'v':
+
+					if bytes.Equal(ffjKeyTopicVehicleNumber, kn) {
+						currentKey = ffjtTopicVehicleNumber
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTopicGeohash, kn) {
+					currentKey = ffjtTopicGeohash
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffjKeyTopicGeohashLevel, kn) {
+					currentKey = ffjtTopicGeohashLevel
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTopicNextStop, kn) {
+					currentKey = ffjtTopicNextStop
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTopicStartTime, kn) {
+					currentKey = ffjtTopicStartTime
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTopicHeadsign, kn) {
+					currentKey = ffjtTopicHeadsign
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTopicDirectionID, kn) {
+					currentKey = ffjtTopicDirectionID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTopicRouteID, kn) {
+					currentKey = ffjtTopicRouteID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTopicVehicleNumber, kn) {
+					currentKey = ffjtTopicVehicleNumber
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTopicOperatorID, kn) {
+					currentKey = ffjtTopicOperatorID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTopicTransportMode, kn) {
+					currentKey = ffjtTopicTransportMode
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffjKeyTopicEventType, kn) {
+					currentKey = ffjtTopicEventType
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffjKeyTopicTemporalType, kn) {
+					currentKey = ffjtTopicTemporalType
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffjKeyTopicJourneyType, kn) {
+					currentKey = ffjtTopicJourneyType
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffjtTopicnosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			}
+
+		case fflib.FFParse_want_colon:
+			if tok != fflib.FFTok_colon {
+				wantedTok = fflib.FFTok_colon
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_value
+			continue
+		case fflib.FFParse_want_value:
+
+			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
+				switch currentKey {
+
+				case ffjtTopicJourneyType:
+					goto handle_JourneyType
+
+				case ffjtTopicTemporalType:
+					goto handle_TemporalType
+
+				case ffjtTopicEventType:
+					goto handle_EventType
+
+				case ffjtTopicTransportMode:
+					goto handle_TransportMode
+
+				case ffjtTopicOperatorID:
+					goto handle_OperatorID
+
+				case ffjtTopicVehicleNumber:
+					goto handle_VehicleNumber
+
+				case ffjtTopicRouteID:
+					goto handle_RouteID
+
+				case ffjtTopicDirectionID:
+					goto handle_DirectionID
+
+				case ffjtTopicHeadsign:
+					goto handle_Headsign
+
+				case ffjtTopicStartTime:
+					goto handle_StartTime
+
+				case ffjtTopicNextStop:
+					goto handle_NextStop
+
+				case ffjtTopicGeohashLevel:
+					goto handle_GeohashLevel
+
+				case ffjtTopicGeohash:
+					goto handle_Geohash
+
+				case ffjtTopicnosuchkey:
+					err = fs.SkipField(tok)
+					if err != nil {
+						return fs.WrapErr(err)
+					}
+					state = fflib.FFParse_after_value
+					goto mainparse
+				}
+			} else {
+				goto wantedvalue
+			}
+		}
+	}
+
+handle_JourneyType:
+
+	/* handler: j.JourneyType type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.JourneyType = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_TemporalType:
+
+	/* handler: j.TemporalType type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.TemporalType = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_EventType:
+
+	/* handler: j.EventType type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.EventType = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_TransportMode:
+
+	/* handler: j.TransportMode type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.TransportMode = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_OperatorID:
+
+	/* handler: j.OperatorID type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.OperatorID = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_VehicleNumber:
+
+	/* handler: j.VehicleNumber type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.VehicleNumber = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_RouteID:
+
+	/* handler: j.RouteID type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.RouteID = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_DirectionID:
+
+	/* handler: j.DirectionID type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.DirectionID = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Headsign:
+
+	/* handler: j.Headsign type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Headsign = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_StartTime:
+
+	/* handler: j.StartTime type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.StartTime = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_NextStop:
+
+	/* handler: j.NextStop type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.NextStop = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_GeohashLevel:
+
+	/* handler: j.GeohashLevel type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.GeohashLevel = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Geohash:
+
+	/* handler: j.Geohash type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Geohash = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+wantedvalue:
+	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+wrongtokenerror:
+	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
+tokerror:
+	if fs.BigError != nil {
+		return fs.WrapErr(fs.BigError)
+	}
+	err = fs.Error.ToError()
+	if err != nil {
+		return fs.WrapErr(err)
+	}
+	panic("ffjson-generated: unreachable, please report bug.")
+done:
+
+	return nil
+}
+
+// MarshalJSON marshal bytes to json - template
+func (j *TrafficLightEvent) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if j == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := j.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSONBuf marshal buff to json - template
+func (j *TrafficLightEvent) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if j == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{"tlp-requestid":`)
+	fflib.FormatBits2(buf, uint64(j.RequestID), 10, j.RequestID < 0)
+	buf.WriteString(`,"tlp-requesttype":`)
+	fflib.WriteJsonString(buf, string(j.RequestType))
+	buf.WriteString(`,"tlp-prioritylevel":`)
+	fflib.WriteJsonString(buf, string(j.PriorityLevel))
+	buf.WriteString(`,"tlp-reason":`)
+	fflib.WriteJsonString(buf, string(j.Reason))
+	buf.WriteString(`,"tlp-att-seq":`)
+	fflib.FormatBits2(buf, uint64(j.AttemptSeq), 10, j.AttemptSeq < 0)
+	buf.WriteString(`,"tlp-decision":`)
+	fflib.WriteJsonString(buf, string(j.Decision))
+	buf.WriteString(`,"sid":`)
+	fflib.FormatBits2(buf, uint64(j.JunctionID), 10, j.JunctionID < 0)
+	buf.WriteString(`,"signal-groupid":`)
+	fflib.FormatBits2(buf, uint64(j.SignalGroupID), 10, j.SignalGroupID < 0)
+	buf.WriteString(`,"tlp-signalgroupnbr":`)
+	fflib.FormatBits2(buf, uint64(j.SignalGroupNbr), 10, j.SignalGroupNbr < 0)
+	buf.WriteString(`,"tlp-line-configid":`)
+	fflib.FormatBits2(buf, uint64(j.LineConfigID), 10, j.LineConfigID < 0)
+	buf.WriteString(`,"tlp-point-configid":`)
+	fflib.FormatBits2(buf, uint64(j.PointConfigID), 10, j.PointConfigID < 0)
+	buf.WriteString(`,"tlp-frequency":`)
+	fflib.FormatBits2(buf, uint64(j.Frequency), 10, j.Frequency < 0)
+	buf.WriteString(`,"tlp-protocol":`)
+	fflib.WriteJsonString(buf, string(j.Protocol))
+	buf.WriteString(`,"jrn":`)
+	fflib.FormatBits2(buf, uint64(j.JrnID), 10, j.JrnID < 0)
+	buf.WriteString(`,"oday":`)
+	fflib.WriteJsonString(buf, string(j.ODay))
+	buf.WriteString(`,"dir":`)
+	fflib.WriteJsonString(buf, string(j.Direction))
+	buf.WriteString(`,"veh":`)
+	fflib.FormatBits2(buf, uint64(j.VehID), 10, j.VehID < 0)
+	buf.WriteString(`,"tsi":`)
+	fflib.FormatBits2(buf, uint64(j.Timestamp), 10, j.Timestamp < 0)
+	buf.WriteString(`,"tst":`)
+	fflib.WriteJsonString(buf, string(j.Tst))
+	buf.WriteString(`,"lat":`)
+	fflib.AppendFloat(buf, float64(j.Lat), 'g', -1, 64)
+	buf.WriteString(`,"long":`)
+	fflib.AppendFloat(buf, float64(j.Lng), 'g', -1, 64)
+	buf.WriteString(`,"hdg":`)
+	fflib.FormatBits2(buf, uint64(j.Heading), 10, j.Heading < 0)
+	buf.WriteString(`,"start":`)
+	fflib.WriteJsonString(buf, string(j.Start))
+	buf.WriteString(`,"dl":`)
+	fflib.AppendFloat(buf, float64(j.DeltaToSchedule), 'g', -1, 32)
+	buf.WriteString(`,"spd":`)
+	fflib.AppendFloat(buf, float64(j.Spd), 'g', -1, 32)
+	buf.WriteString(`,"acc":`)
+	fflib.AppendFloat(buf, float64(j.Acc), 'g', -1, 32)
+	buf.WriteString(`,"route":`)
+	fflib.WriteJsonString(buf, string(j.RouteID))
+	buf.WriteString(`,"stop":`)
+
+	{
+
+		obj, err = j.Stop.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteString(`,"odo":`)
+
+	{
+
+		obj, err = j.Odometer.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(obj)
+
+	}
+	buf.WriteString(`,"occu":`)
+	fflib.FormatBits2(buf, uint64(j.Occupancy), 10, j.Occupancy < 0)
+	buf.WriteByte('}')
+	return nil
+}
+
+const (
+	ffjtTrafficLightEventbase = iota
+	ffjtTrafficLightEventnosuchkey
+
+	ffjtTrafficLightEventRequestID
+
+	ffjtTrafficLightEventRequestType
+
+	ffjtTrafficLightEventPriorityLevel
+
+	ffjtTrafficLightEventReason
+
+	ffjtTrafficLightEventAttemptSeq
+
+	ffjtTrafficLightEventDecision
+
+	ffjtTrafficLightEventJunctionID
+
+	ffjtTrafficLightEventSignalGroupID
+
+	ffjtTrafficLightEventSignalGroupNbr
+
+	ffjtTrafficLightEventLineConfigID
+
+	ffjtTrafficLightEventPointConfigID
+
+	ffjtTrafficLightEventFrequency
+
+	ffjtTrafficLightEventProtocol
+
+	ffjtTrafficLightEventJrnID
+
+	ffjtTrafficLightEventODay
+
+	ffjtTrafficLightEventDirection
+
+	ffjtTrafficLightEventVehID
+
+	ffjtTrafficLightEventTimestamp
+
+	ffjtTrafficLightEventTst
+
+	ffjtTrafficLightEventLat
+
+	ffjtTrafficLightEventLng
+
+	ffjtTrafficLightEventHeading
+
+	ffjtTrafficLightEventStart
+
+	ffjtTrafficLightEventDeltaToSchedule
+
+	ffjtTrafficLightEventSpd
+
+	ffjtTrafficLightEventAcc
+
+	ffjtTrafficLightEventRouteID
+
+	ffjtTrafficLightEventStop
+
+	ffjtTrafficLightEventOdometer
+
+	ffjtTrafficLightEventOccupancy
+)
+
+var ffjKeyTrafficLightEventRequestID = []byte("tlp-requestid")
+
+var ffjKeyTrafficLightEventRequestType = []byte("tlp-requesttype")
+
+var ffjKeyTrafficLightEventPriorityLevel = []byte("tlp-prioritylevel")
+
+var ffjKeyTrafficLightEventReason = []byte("tlp-reason")
+
+var ffjKeyTrafficLightEventAttemptSeq = []byte("tlp-att-seq")
+
+var ffjKeyTrafficLightEventDecision = []byte("tlp-decision")
+
+var ffjKeyTrafficLightEventJunctionID = []byte("sid")
+
+var ffjKeyTrafficLightEventSignalGroupID = []byte("signal-groupid")
+
+var ffjKeyTrafficLightEventSignalGroupNbr = []byte("tlp-signalgroupnbr")
+
+var ffjKeyTrafficLightEventLineConfigID = []byte("tlp-line-configid")
+
+var ffjKeyTrafficLightEventPointConfigID = []byte("tlp-point-configid")
+
+var ffjKeyTrafficLightEventFrequency = []byte("tlp-frequency")
+
+var ffjKeyTrafficLightEventProtocol = []byte("tlp-protocol")
+
+var ffjKeyTrafficLightEventJrnID = []byte("jrn")
+
+var ffjKeyTrafficLightEventODay = []byte("oday")
+
+var ffjKeyTrafficLightEventDirection = []byte("dir")
+
+var ffjKeyTrafficLightEventVehID = []byte("veh")
+
+var ffjKeyTrafficLightEventTimestamp = []byte("tsi")
+
+var ffjKeyTrafficLightEventTst = []byte("tst")
+
+var ffjKeyTrafficLightEventLat = []byte("lat")
+
+var ffjKeyTrafficLightEventLng = []byte("long")
+
+var ffjKeyTrafficLightEventHeading = []byte("hdg")
+
+var ffjKeyTrafficLightEventStart = []byte("start")
+
+var ffjKeyTrafficLightEventDeltaToSchedule = []byte("dl")
+
+var ffjKeyTrafficLightEventSpd = []byte("spd")
+
+var ffjKeyTrafficLightEventAcc = []byte("acc")
+
+var ffjKeyTrafficLightEventRouteID = []byte("route")
+
+var ffjKeyTrafficLightEventStop = []byte("stop")
+
+var ffjKeyTrafficLightEventOdometer = []byte("odo")
+
+var ffjKeyTrafficLightEventOccupancy = []byte("occu")
+
+// UnmarshalJSON umarshall json - template of ffjson
+func (j *TrafficLightEvent) UnmarshalJSON(input []byte) error {
+	fs := fflib.NewFFLexer(input)
+	return j.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
+}
+
+// UnmarshalJSONFFLexer fast json unmarshall - template ffjson
+func (j *TrafficLightEvent) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+	var err error
+	currentKey := ffjtTrafficLightEventbase
+	_ = currentKey
+	tok := fflib.FFTok_init
+	wantedTok := fflib.FFTok_init
+
+mainparse:
+	for {
+		tok = fs.Scan()
+		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
+		if tok == fflib.FFTok_error {
+			goto tokerror
+		}
+
+		switch state {
+
+		case fflib.FFParse_map_start:
+			if tok != fflib.FFTok_left_bracket {
+				wantedTok = fflib.FFTok_left_bracket
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_key
+			continue
+
+		case fflib.FFParse_after_value:
+			if tok == fflib.FFTok_comma {
+				state = fflib.FFParse_want_key
+			} else if tok == fflib.FFTok_right_bracket {
+				goto done
+			} else {
+				wantedTok = fflib.FFTok_comma
+				goto wrongtokenerror
+			}
+
+		case fflib.FFParse_want_key:
+			// json {} ended. goto exit. woo.
+			if tok == fflib.FFTok_right_bracket {
+				goto done
+			}
+			if tok != fflib.FFTok_string {
+				wantedTok = fflib.FFTok_string
+				goto wrongtokenerror
+			}
+
+			kn := fs.Output.Bytes()
+			if len(kn) <= 0 {
+				// "" case. hrm.
+				currentKey = ffjtTrafficLightEventnosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			} else {
+				switch kn[0] {
+
+				case 'a':
+
+					if bytes.Equal(ffjKeyTrafficLightEventAcc, kn) {
+						currentKey = ffjtTrafficLightEventAcc
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'd':
+
+					if bytes.Equal(ffjKeyTrafficLightEventDirection, kn) {
+						currentKey = ffjtTrafficLightEventDirection
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventDeltaToSchedule, kn) {
+						currentKey = ffjtTrafficLightEventDeltaToSchedule
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'h':
+
+					if bytes.Equal(ffjKeyTrafficLightEventHeading, kn) {
+						currentKey = ffjtTrafficLightEventHeading
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'j':
+
+					if bytes.Equal(ffjKeyTrafficLightEventJrnID, kn) {
+						currentKey = ffjtTrafficLightEventJrnID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'l':
+
+					if bytes.Equal(ffjKeyTrafficLightEventLat, kn) {
+						currentKey = ffjtTrafficLightEventLat
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventLng, kn) {
+						currentKey = ffjtTrafficLightEventLng
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'o':
+
+					if bytes.Equal(ffjKeyTrafficLightEventODay, kn) {
+						currentKey = ffjtTrafficLightEventODay
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventOdometer, kn) {
+						currentKey = ffjtTrafficLightEventOdometer
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventOccupancy, kn) {
+						currentKey = ffjtTrafficLightEventOccupancy
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'r':
+
+					if bytes.Equal(ffjKeyTrafficLightEventRouteID, kn) {
+						currentKey = ffjtTrafficLightEventRouteID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 's':
+
+					if bytes.Equal(ffjKeyTrafficLightEventJunctionID, kn) {
+						currentKey = ffjtTrafficLightEventJunctionID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventSignalGroupID, kn) {
+						currentKey = ffjtTrafficLightEventSignalGroupID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventStart, kn) {
+						currentKey = ffjtTrafficLightEventStart
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventSpd, kn) {
+						currentKey = ffjtTrafficLightEventSpd
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventStop, kn) {
+						currentKey = ffjtTrafficLightEventStop
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 't':
+
+					if bytes.Equal(ffjKeyTrafficLightEventRequestID, kn) {
+						currentKey = ffjtTrafficLightEventRequestID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventRequestType, kn) {
+						currentKey = ffjtTrafficLightEventRequestType
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventPriorityLevel, kn) {
+						currentKey = ffjtTrafficLightEventPriorityLevel
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventReason, kn) {
+						currentKey = ffjtTrafficLightEventReason
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventAttemptSeq, kn) {
+						currentKey = ffjtTrafficLightEventAttemptSeq
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventDecision, kn) {
+						currentKey = ffjtTrafficLightEventDecision
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventSignalGroupNbr, kn) {
+						currentKey = ffjtTrafficLightEventSignalGroupNbr
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventLineConfigID, kn) {
+						currentKey = ffjtTrafficLightEventLineConfigID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventPointConfigID, kn) {
+						currentKey = ffjtTrafficLightEventPointConfigID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventFrequency, kn) {
+						currentKey = ffjtTrafficLightEventFrequency
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventProtocol, kn) {
+						currentKey = ffjtTrafficLightEventProtocol
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventTimestamp, kn) {
+						currentKey = ffjtTrafficLightEventTimestamp
+						state = fflib.FFParse_want_colon
+						goto mainparse
+
+					} else if bytes.Equal(ffjKeyTrafficLightEventTst, kn) {
+						currentKey = ffjtTrafficLightEventTst
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'v':
+
+					if bytes.Equal(ffjKeyTrafficLightEventVehID, kn) {
+						currentKey = ffjtTrafficLightEventVehID
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventOccupancy, kn) {
+					currentKey = ffjtTrafficLightEventOccupancy
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventOdometer, kn) {
+					currentKey = ffjtTrafficLightEventOdometer
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventStop, kn) {
+					currentKey = ffjtTrafficLightEventStop
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventRouteID, kn) {
+					currentKey = ffjtTrafficLightEventRouteID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventAcc, kn) {
+					currentKey = ffjtTrafficLightEventAcc
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventSpd, kn) {
+					currentKey = ffjtTrafficLightEventSpd
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventDeltaToSchedule, kn) {
+					currentKey = ffjtTrafficLightEventDeltaToSchedule
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventStart, kn) {
+					currentKey = ffjtTrafficLightEventStart
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventHeading, kn) {
+					currentKey = ffjtTrafficLightEventHeading
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventLng, kn) {
+					currentKey = ffjtTrafficLightEventLng
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventLat, kn) {
+					currentKey = ffjtTrafficLightEventLat
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventTst, kn) {
+					currentKey = ffjtTrafficLightEventTst
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventTimestamp, kn) {
+					currentKey = ffjtTrafficLightEventTimestamp
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventVehID, kn) {
+					currentKey = ffjtTrafficLightEventVehID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventDirection, kn) {
+					currentKey = ffjtTrafficLightEventDirection
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventODay, kn) {
+					currentKey = ffjtTrafficLightEventODay
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyTrafficLightEventJrnID, kn) {
+					currentKey = ffjtTrafficLightEventJrnID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffjKeyTrafficLightEventProtocol, kn) {
+					currentKey = ffjtTrafficLightEventProtocol
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffjKeyTrafficLightEventFrequency, kn) {
+					currentKey = ffjtTrafficLightEventFrequency
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffjKeyTrafficLightEventPointConfigID, kn) {
+					currentKey = ffjtTrafficLightEventPointConfigID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffjKeyTrafficLightEventLineConfigID, kn) {
+					currentKey = ffjtTrafficLightEventLineConfigID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventSignalGroupNbr, kn) {
+					currentKey = ffjtTrafficLightEventSignalGroupNbr
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventSignalGroupID, kn) {
+					currentKey = ffjtTrafficLightEventSignalGroupID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventJunctionID, kn) {
+					currentKey = ffjtTrafficLightEventJunctionID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventDecision, kn) {
+					currentKey = ffjtTrafficLightEventDecision
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventAttemptSeq, kn) {
+					currentKey = ffjtTrafficLightEventAttemptSeq
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventReason, kn) {
+					currentKey = ffjtTrafficLightEventReason
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.AsciiEqualFold(ffjKeyTrafficLightEventPriorityLevel, kn) {
+					currentKey = ffjtTrafficLightEventPriorityLevel
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventRequestType, kn) {
+					currentKey = ffjtTrafficLightEventRequestType
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyTrafficLightEventRequestID, kn) {
+					currentKey = ffjtTrafficLightEventRequestID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffjtTrafficLightEventnosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			}
+
+		case fflib.FFParse_want_colon:
+			if tok != fflib.FFTok_colon {
+				wantedTok = fflib.FFTok_colon
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_value
+			continue
+		case fflib.FFParse_want_value:
+
+			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
+				switch currentKey {
+
+				case ffjtTrafficLightEventRequestID:
+					goto handle_RequestID
+
+				case ffjtTrafficLightEventRequestType:
+					goto handle_RequestType
+
+				case ffjtTrafficLightEventPriorityLevel:
+					goto handle_PriorityLevel
+
+				case ffjtTrafficLightEventReason:
+					goto handle_Reason
+
+				case ffjtTrafficLightEventAttemptSeq:
+					goto handle_AttemptSeq
+
+				case ffjtTrafficLightEventDecision:
+					goto handle_Decision
+
+				case ffjtTrafficLightEventJunctionID:
+					goto handle_JunctionID
+
+				case ffjtTrafficLightEventSignalGroupID:
+					goto handle_SignalGroupID
+
+				case ffjtTrafficLightEventSignalGroupNbr:
+					goto handle_SignalGroupNbr
+
+				case ffjtTrafficLightEventLineConfigID:
+					goto handle_LineConfigID
+
+				case ffjtTrafficLightEventPointConfigID:
+					goto handle_PointConfigID
+
+				case ffjtTrafficLightEventFrequency:
+					goto handle_Frequency
+
+				case ffjtTrafficLightEventProtocol:
+					goto handle_Protocol
+
+				case ffjtTrafficLightEventJrnID:
+					goto handle_JrnID
+
+				case ffjtTrafficLightEventODay:
+					goto handle_ODay
+
+				case ffjtTrafficLightEventDirection:
+					goto handle_Direction
+
+				case ffjtTrafficLightEventVehID:
+					goto handle_VehID
+
+				case ffjtTrafficLightEventTimestamp:
+					goto handle_Timestamp
+
+				case ffjtTrafficLightEventTst:
+					goto handle_Tst
+
+				case ffjtTrafficLightEventLat:
+					goto handle_Lat
+
+				case ffjtTrafficLightEventLng:
+					goto handle_Lng
+
+				case ffjtTrafficLightEventHeading:
+					goto handle_Heading
+
+				case ffjtTrafficLightEventStart:
+					goto handle_Start
+
+				case ffjtTrafficLightEventDeltaToSchedule:
+					goto handle_DeltaToSchedule
+
+				case ffjtTrafficLightEventSpd:
+					goto handle_Spd
+
+				case ffjtTrafficLightEventAcc:
+					goto handle_Acc
+
+				case ffjtTrafficLightEventRouteID:
+					goto handle_RouteID
+
+				case ffjtTrafficLightEventStop:
+					goto handle_Stop
+
+				case ffjtTrafficLightEventOdometer:
+					goto handle_Odometer
+
+				case ffjtTrafficLightEventOccupancy:
+					goto handle_Occupancy
+
+				case ffjtTrafficLightEventnosuchkey:
+					err = fs.SkipField(tok)
+					if err != nil {
+						return fs.WrapErr(err)
+					}
+					state = fflib.FFParse_after_value
+					goto mainparse
+				}
+			} else {
+				goto wantedvalue
+			}
+		}
+	}
+
+handle_RequestID:
+
+	/* handler: j.RequestID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.RequestID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_RequestType:
+
+	/* handler: j.RequestType type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.RequestType = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_PriorityLevel:
+
+	/* handler: j.PriorityLevel type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.PriorityLevel = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Reason:
+
+	/* handler: j.Reason type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Reason = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_AttemptSeq:
+
+	/* handler: j.AttemptSeq type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.AttemptSeq = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Decision:
+
+	/* handler: j.Decision type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Decision = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_JunctionID:
+
+	/* handler: j.JunctionID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.JunctionID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_SignalGroupID:
+
+	/* handler: j.SignalGroupID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.SignalGroupID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_SignalGroupNbr:
+
+	/* handler: j.SignalGroupNbr type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.SignalGroupNbr = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_LineConfigID:
+
+	/* handler: j.LineConfigID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.LineConfigID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_PointConfigID:
+
+	/* handler: j.PointConfigID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.PointConfigID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Frequency:
+
+	/* handler: j.Frequency type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Frequency = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Protocol:
+
+	/* handler: j.Protocol type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Protocol = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_JrnID:
+
+	/* handler: j.JrnID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.JrnID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_ODay:
+
+	/* handler: j.ODay type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.ODay = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Direction:
+
+	/* handler: j.Direction type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Direction = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_VehID:
+
+	/* handler: j.VehID type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.VehID = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Timestamp:
+
+	/* handler: j.Timestamp type=int64 kind=int64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Timestamp = int64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Tst:
+
+	/* handler: j.Tst type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Tst = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Lat:
+
+	/* handler: j.Lat type=float64 kind=float64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Lat = float64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Lng:
+
+	/* handler: j.Lng type=float64 kind=float64 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float64", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Lng = float64(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Heading:
+
+	/* handler: j.Heading type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Heading = int(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Start:
+
+	/* handler: j.Start type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Start = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_DeltaToSchedule:
+
+	/* handler: j.DeltaToSchedule type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.DeltaToSchedule = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Spd:
+
+	/* handler: j.Spd type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Spd = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Acc:
+
+	/* handler: j.Acc type=float32 kind=float32 quoted=false*/
+
+	{
+		if tok != fflib.FFTok_double && tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for float32", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseFloat(fs.Output.Bytes(), 32)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Acc = float32(tval)
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_RouteID:
+
+	/* handler: j.RouteID type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.RouteID = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Stop:
+
+	/* handler: j.Stop type=hsldatabridge.OptInt kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tbuf, err := fs.CaptureField(tok)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			err = j.Stop.UnmarshalJSON(tbuf)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Odometer:
+
+	/* handler: j.Odometer type=hsldatabridge.OptInt kind=struct quoted=false*/
+
+	{
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tbuf, err := fs.CaptureField(tok)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			err = j.Odometer.UnmarshalJSON(tbuf)
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+		}
+		state = fflib.FFParse_after_value
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Occupancy:
+
+	/* handler: j.Occupancy type=int kind=int quoted=false*/
+
+	{
+		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
+			return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for int", tok))
+		}
+	}
+
+	{
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			tval, err := fflib.ParseInt(fs.Output.Bytes(), 10, 64)
+
+			if err != nil {
+				return fs.WrapErr(err)
+			}
+
+			j.Occupancy = int(tval)
+
+		}
 	}
 
 	state = fflib.FFParse_after_value
@@ -0,0 +1,113 @@
+package hsldatabridge
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTopic(t *testing.T) {
+
+	for _, tc := range []struct {
+		name  string
+		topic string
+		want  *Topic
+	}{
+		{
+			name:  "full",
+			topic: "/hfp/v2/journey/ongoing/vp/bus/0018/00423/2159/2/Matinkylä (M)/09:32/2442201/3/60;24/16/58/67",
+			want: &Topic{
+				JourneyType: "journey", TemporalType: "ongoing", EventType: "vp", TransportMode: "bus",
+				OperatorID: "0018", VehicleNumber: "00423", RouteID: "2159", DirectionID: "2",
+				Headsign: "Matinkylä (M)", StartTime: "09:32", NextStop: "2442201",
+				GeohashLevel: 3, Geohash: "60;24/16/58/67",
+			},
+		},
+		{
+			name:  "upcoming stop event at end of line",
+			topic: "/hfp/v2/journey/upcoming/arr/tram/0040/00412/1009/1/Länsiterminaali T2/10:05/EOL/4/60;24/19/46/82",
+			want: &Topic{
+				JourneyType: "journey", TemporalType: "upcoming", EventType: "arr", TransportMode: "tram",
+				OperatorID: "0040", VehicleNumber: "00412", RouteID: "1009", DirectionID: "1",
+				Headsign: "Länsiterminaali T2", StartTime: "10:05", NextStop: "EOL",
+				GeohashLevel: 4, Geohash: "60;24/19/46/82",
+			},
+		},
+		{
+			name:  "w.o. location",
+			topic: "/hfp/v2/journey/ongoing/doo/metro/0050/00123/31M2/2/Vuosaari/09:40/1020602/",
+			want: &Topic{
+				JourneyType: "journey", TemporalType: "ongoing", EventType: "doo", TransportMode: "metro",
+				OperatorID: "0050", VehicleNumber: "00123", RouteID: "31M2", DirectionID: "2",
+				Headsign: "Vuosaari", StartTime: "09:40", NextStop: "1020602",
+			},
+		},
+		{
+			name:  "deadrun w. empty journey levels",
+			topic: "/hfp/v2/deadrun/ongoing/vp/bus/0022/00845//////0/60;25/20/21/45",
+			want: &Topic{
+				JourneyType: "deadrun", TemporalType: "ongoing", EventType: "vp", TransportMode: "bus",
+				OperatorID: "0022", VehicleNumber: "00845", GeohashLevel: 0, Geohash: "60;25/20/21/45",
+			},
+		},
+		{
+			name:  "deadrun ending at the vehicle number",
+			topic: "/hfp/v2/deadrun/upcoming/da/ubus/0012/01502",
+			want: &Topic{
+				JourneyType: "deadrun", TemporalType: "upcoming", EventType: "da", TransportMode: "ubus",
+				OperatorID: "0012", VehicleNumber: "01502",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseTopic(tc.topic)
+			if err != nil {
+				t.Fatalf("ParseTopic(%q): %+v", tc.topic, err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ParseTopic(%q)\n got: %+v\nwant: %+v", tc.topic, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseTopicInvalid(t *testing.T) {
+
+	for _, topic := range []string{
+		"",
+		"/hfp/v2/journey/ongoing/vp/bus/0018",
+		"/hfp/v1/journey/ongoing/vp/bus/0018/00423/2159",
+		"hfp/v2/journey/ongoing/vp/bus/0018/00423/2159",
+		"/gtfsrt/v2/journey/ongoing/vp/bus/0018/00423/2159",
+	} {
+		_, err := ParseTopic(topic)
+		if _, ok := err.(*MQTTValidationError); !ok {
+			t.Errorf("ParseTopic(%q): got error %v, want *MQTTValidationError", topic, err)
+		}
+	}
+}
+
+func TestFillFromTopic(t *testing.T) {
+
+	topic, err := ParseTopic("/hfp/v2/journey/ongoing/vp/bus/0018/00423/2159/2/Matinkylä (M)/09:32/2442201/3/60;24/16/58/67")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Missing from the body, filled from the topic
+	e := &Event{}
+	e.fillFromTopic(topic)
+
+	want := Event{RouteID: "2159", Direction: "2", Start: "09:32", VehID: 423}
+	if *e != want {
+		t.Errorf("fillFromTopic on empty body: got %+v, want %+v", *e, want)
+	}
+
+	// Sent in the body, kept
+	e = &Event{RouteID: "2159N", Direction: "1", Start: "09:30", VehID: 7}
+	want = *e
+	e.fillFromTopic(topic)
+
+	if *e != want {
+		t.Errorf("fillFromTopic on full body: got %+v, want %+v", *e, want)
+	}
+}
@@ -0,0 +1,199 @@
+package hsldatabridge
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// JourneyCache - An in-memory LRU of journey IDs known to exist in Redis (i.e. w.
+// their timeseries created), checked before Redis s.t. only new journeys cost a
+// round-trip. Entries expire TTL after they're added, the next event for the journey
+// checks Redis again; this picks up journeys removed from Redis by another process.
+//
+// Safe for concurrent use, see Ensure for workers racing on the same new journey
+type JourneyCache struct {
+	mu    sync.Mutex
+	ll    *list.List // Most recently used at the front
+	items map[string]*list.Element
+	size  int
+	ttl   time.Duration
+
+	group singleflight.Group
+}
+
+// recentSamples - samples per journey remembered by Duplicate, i.e. ~30s of positions
+const recentSamples = 32
+
+type journeyEntry struct {
+	id      string
+	expires time.Time
+	latest  int64 // Latest sample (unix ms) written to the journey's series, see Observe
+
+	recent [recentSamples]int64 // Ring of the last samples (unix ms) written, see Duplicate
+	next   int
+}
+
+// NewJourneyCache - creates a cache holding up to size journeys, each for up
+// to ttl
+func NewJourneyCache(size int, ttl time.Duration) *JourneyCache {
+	return &JourneyCache{
+		ll:    list.New(),
+		items: make(map[string]*list.Element, size),
+		size:  size,
+		ttl:   ttl,
+	}
+}
+
+// Contains - reports whether the journey is cached and not expired
+func (c *JourneyCache) Contains(id string) bool {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[id]
+	if !ok {
+		return false
+	}
+
+	if time.Now().After(el.Value.(*journeyEntry).expires) {
+		c.ll.Remove(el)
+		delete(c.items, id)
+		return false
+	}
+
+	c.ll.MoveToFront(el)
+	return true
+}
+
+// Add - caches the journey, evicting the least recently used journey if full
+func (c *JourneyCache) Add(id string) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := time.Now().Add(c.ttl)
+
+	if el, ok := c.items[id]; ok {
+		el.Value.(*journeyEntry).expires = expires
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[id] = c.ll.PushFront(&journeyEntry{id: id, expires: expires})
+
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*journeyEntry).id)
+	}
+}
+
+// Remove - drops the journey from the cache, e.g. once its series are deleted
+func (c *JourneyCache) Remove(id string) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[id]; ok {
+		c.ll.Remove(el)
+		delete(c.items, id)
+	}
+}
+
+// Observe - records a sample at ts (unix ms) for the journey, returns the latest
+// sample recorded before it, or 0 if the journey isn't cached
+func (c *JourneyCache) Observe(id string, ts int64) int64 {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[id]
+	if !ok {
+		return 0
+	}
+
+	e := el.Value.(*journeyEntry)
+	latest := e.latest
+
+	if ts > latest {
+		e.latest = ts
+	}
+
+	return latest
+}
+
+// Duplicate - reports whether a sample at ts (unix ms) was already written for the
+// journey (see Written), e.g. a position redelivered by the broker. Only the last
+// recentSamples samples of a cached journey are remembered
+func (c *JourneyCache) Duplicate(id string, ts int64) bool {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[id]
+	if !ok {
+		return false
+	}
+
+	for _, written := range el.Value.(*journeyEntry).recent {
+		if written == ts {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Written - records a sample at ts (unix ms) as written for the journey, see Duplicate
+func (c *JourneyCache) Written(id string, ts int64) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[id]; ok {
+		e := el.Value.(*journeyEntry)
+		e.recent[e.next%recentSamples] = ts
+		e.next++
+	}
+}
+
+// Len - returns the count of cached journeys, incl. any expired but not yet evicted
+func (c *JourneyCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Ensure - calls register for the journey unless it's cached, caching it once
+// register succeeds. Concurrent calls for the same journey share a single call
+// to register, s.t. workers racing on the first events of a journey don't
+// create its series more than once
+func (c *JourneyCache) Ensure(id string, register func() error) error {
+
+	if c.Contains(id) {
+		journeyCacheLookups.WithLabelValues("hit").Inc()
+		return nil
+	}
+
+	journeyCacheLookups.WithLabelValues("miss").Inc()
+
+	_, err, _ := c.group.Do(id, func() (interface{}, error) {
+
+		// Registered by a call that finished between Contains and Do
+		if c.Contains(id) {
+			return nil, nil
+		}
+
+		if err := register(); err != nil {
+			return nil, err
+		}
+
+		c.Add(id)
+		return nil, nil
+	})
+
+	return err
+}
@@ -0,0 +1,163 @@
+package hsldatabridge
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJourneyCacheHit(t *testing.T) {
+
+	c := NewJourneyCache(10, time.Hour)
+
+	if c.Contains("a") {
+		t.Fatalf("Contains before Add: got true")
+	}
+
+	c.Add("a")
+	if !c.Contains("a") {
+		t.Errorf("Contains after Add: got false")
+	}
+
+	c.Remove("a")
+	if c.Contains("a") || c.Len() != 0 {
+		t.Errorf("Contains after Remove: got true, Len %d", c.Len())
+	}
+}
+
+func TestJourneyCacheExpires(t *testing.T) {
+
+	c := NewJourneyCache(10, 20*time.Millisecond)
+	c.Add("a")
+
+	if !c.Contains("a") {
+		t.Fatalf("Contains before TTL: got false")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if c.Contains("a") {
+		t.Errorf("Contains after TTL: got true")
+	}
+
+	// Expired entries are evicted on lookup
+	if c.Len() != 0 {
+		t.Errorf("Len after TTL: got %d, want 0", c.Len())
+	}
+
+	// Adding again starts a new TTL
+	c.Add("a")
+	if !c.Contains("a") {
+		t.Errorf("Contains after re-Add: got false")
+	}
+}
+
+func TestJourneyCacheEvictsLeastRecentlyUsed(t *testing.T) {
+
+	c := NewJourneyCache(2, time.Hour)
+	c.Add("a")
+	c.Add("b")
+
+	// "a" is now the most recently used, "b" is evicted
+	c.Contains("a")
+	c.Add("c")
+
+	if c.Len() != 2 {
+		t.Errorf("Len at capacity: got %d, want 2", c.Len())
+	}
+
+	for id, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if got := c.Contains(id); got != want {
+			t.Errorf("Contains(%s): got %t, want %t", id, got, want)
+		}
+	}
+}
+
+func TestJourneyCacheEnsureRegistersOnce(t *testing.T) {
+
+	c := NewJourneyCache(10, time.Hour)
+
+	var (
+		registered int32
+		start      = make(chan struct{})
+		wg         sync.WaitGroup
+	)
+
+	register := func() error {
+		atomic.AddInt32(&registered, 1)
+		time.Sleep(10 * time.Millisecond) // A round-trip, s.t. the other callers pile up
+		return nil
+	}
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if err := c.Ensure("a", register); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&registered); n != 1 {
+		t.Errorf("got %d registrations, want 1", n)
+	}
+
+	// Cached from here on
+	c.Ensure("a", register)
+	if n := atomic.LoadInt32(&registered); n != 1 {
+		t.Errorf("got %d registrations after a hit, want 1", n)
+	}
+}
+
+func TestJourneyCacheEnsureFails(t *testing.T) {
+
+	c := NewJourneyCache(10, time.Hour)
+
+	// Not cached if register fails, the next call tries again
+	if err := c.Ensure("a", func() error { return errors.New("redis down") }); err == nil {
+		t.Fatalf("Ensure: got nil error")
+	}
+
+	if c.Contains("a") {
+		t.Errorf("Contains after a failed Ensure: got true")
+	}
+
+	var calls int
+	if err := c.Ensure("a", func() error { calls++; return nil }); err != nil || calls != 1 {
+		t.Errorf("Ensure after a failure: got %v, %d calls", err, calls)
+	}
+}
+
+func TestJourneyCacheDuplicate(t *testing.T) {
+
+	c := NewJourneyCache(10, time.Hour)
+
+	// Not cached, nothing remembered
+	c.Written("a", 1000)
+	if c.Duplicate("a", 1000) {
+		t.Errorf("Duplicate for an uncached journey: got true")
+	}
+
+	c.Add("a")
+	c.Written("a", 1000)
+
+	if !c.Duplicate("a", 1000) || c.Duplicate("a", 2000) || c.Duplicate("b", 1000) {
+		t.Errorf("Duplicate: unexpected result after writing 1000 to a")
+	}
+
+	// Only the last recentSamples are remembered
+	for i := 1; i <= recentSamples; i++ {
+		c.Written("a", int64(1000+i))
+	}
+
+	if c.Duplicate("a", 1000) || !c.Duplicate("a", int64(1000+recentSamples)) {
+		t.Errorf("Duplicate: unexpected result after %d more samples", recentSamples)
+	}
+}
@@ -0,0 +1,233 @@
+package hsldatabridge
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// journeySetKey - set of journeys w. their timeseries created, see statJourneyID
+	journeySetKey = "journeyID"
+
+	// journeyLastSeenKey - sorted set of journeys scored by the time (unix ms) of
+	// their last position
+	journeyLastSeenKey = "journeys:lastseen"
+
+	// journeyEndedStream - stream of journey-ended events for downstream consumers
+	journeyEndedStream = "journeys:ended"
+
+	// journeyReapBatch - max finished journeys cleaned up per reap
+	journeyReapBatch = 1000
+)
+
+// JourneyState - where a journey is in its lifecycle
+type JourneyState string
+
+// Journey states
+//
+//   - active: a position was received in the last `journeys.idle_after`
+//   - idle: no position for `journeys.idle_after`, the journey's series are kept
+//   - finished: the vehicle signed out of the journey (VJOUT), or no position for
+//     `journeys.finish_after`; series are deleted and the journey is forgotten
+const (
+	JourneyActive   JourneyState = "active"
+	JourneyIdle     JourneyState = "idle"
+	JourneyFinished JourneyState = "finished"
+)
+
+// Reasons a journey finished, written to the journey-ended event
+const (
+	JourneyEndSignOut = "vjout"
+	JourneyEndIdle    = "idle"
+)
+
+// finishJourneyScript - atomically forgets a journey, deletes its series, and emits
+// a journey-ended event; returns 0 if the journey was already finished (e.g. by
+// another connector), or if a position arrived after `cutoff` (idle only)
+//
+// KEYS: lastseen zset, journey set, ended stream, series... (may be none)
+// ARGV: journeyID, reason, ended (ms), stream maxlen, cutoff (ms, or +inf)
+var finishJourneyScript = redis.NewScript(`
+local seen = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if seen and ARGV[5] ~= '+inf' and tonumber(seen) > tonumber(ARGV[5]) then
+	return 0
+end
+
+local removed = redis.call('ZREM', KEYS[1], ARGV[1]) + redis.call('SREM', KEYS[2], ARGV[1])
+if removed == 0 then
+	return 0
+end
+
+-- NOTE: No series keys if timeseries.series is empty, DEL w.o. keys is an error
+if #KEYS >= 4 then
+	redis.call('DEL', unpack(KEYS, 4))
+end
+redis.call('XADD', KEYS[3], 'MAXLEN', '~', ARGV[4], '*',
+	'jid', ARGV[1], 'reason', ARGV[2], 'lastseen', seen or '', 'ended', ARGV[3])
+return 1
+`)
+
+// JourneyTracker - Tracks journeys through their lifecycle (see JourneyState) using
+// the time of each journey's last position, and cleans up finished journeys. State
+// is kept in Redis s.t. several connectors can share it
+type JourneyTracker struct {
+	IdleAfter   time.Duration
+	FinishAfter time.Duration
+	EndedMaxLen int64 // Approx. max length of the journey-ended stream
+
+	// Series - the timeseries deleted when a journey finishes, see SeriesDef
+	Series []SeriesDef
+
+	client   *redis.Client
+	journeys *JourneyCache
+}
+
+// NewJourneyTracker - creates a tracker using the timeouts in cfg; finished journeys
+// are removed from journeys s.t. late positions register the journey again
+func NewJourneyTracker(client *redis.Client, journeys *JourneyCache, cfg JourneysConfig) *JourneyTracker {
+	return &JourneyTracker{
+		IdleAfter:   cfg.IdleAfter,
+		FinishAfter: cfg.FinishAfter,
+		EndedMaxLen: 100000,
+		Series:      DefaultSeries(),
+		client:      client,
+		journeys:    journeys,
+	}
+}
+
+// Seen - marks the journey active, added to the sink's pipeline w. each position
+func (t *JourneyTracker) Seen(ctx context.Context, pipe redis.Pipeliner, journeyID string, at time.Time) {
+	pipe.ZAdd(ctx, journeyLastSeenKey, &redis.Z{
+		Score: float64(at.UnixNano() / int64(time.Millisecond)), Member: journeyID,
+	})
+}
+
+// State - returns the journey's state; journeys never seen are reported as finished
+func (t *JourneyTracker) State(ctx context.Context, journeyID string) (JourneyState, error) {
+
+	ms, err := t.client.ZScore(ctx, journeyLastSeenKey, journeyID).Result()
+	if err == redis.Nil {
+		return JourneyFinished, nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	switch idle := time.Since(time.Unix(0, int64(ms)*int64(time.Millisecond))); {
+	case idle < t.IdleAfter:
+		return JourneyActive, nil
+	case idle < t.FinishAfter:
+		return JourneyIdle, nil
+	default:
+		return JourneyFinished, nil
+	}
+}
+
+// Finish - finishes the journey, returns false if it had already finished
+func (t *JourneyTracker) Finish(ctx context.Context, journeyID string, reason string) (bool, error) {
+	return t.finish(ctx, journeyID, reason, "+inf")
+}
+
+func (t *JourneyTracker) finish(ctx context.Context, journeyID string, reason string, cutoff string) (bool, error) {
+
+	keys := append(
+		[]string{journeyLastSeenKey, journeySetKey, journeyEndedStream},
+		journeySeriesKeys(t.Series, journeyID)...,
+	)
+
+	n, err := finishJourneyScript.Run(
+		ctx, t.client, keys,
+		journeyID, reason, time.Now().UnixNano()/int64(time.Millisecond), t.EndedMaxLen, cutoff,
+	).Int()
+
+	if err != nil {
+		return false, err
+	}
+
+	t.journeys.Remove(journeyID)
+
+	if n == 0 {
+		return false, nil
+	}
+
+	journeysFinished.WithLabelValues(reason).Inc()
+	log.WithFields(
+		log.Fields{"JourneyID": journeyID, "Reason": reason},
+	).Info("Journey Finished")
+
+	return true, nil
+}
+
+// Reap - finishes journeys w.o. a position for FinishAfter, up to journeyReapBatch
+// per call, and updates the active/idle journey counts; returns the number finished
+func (t *JourneyTracker) Reap(ctx context.Context) (int, error) {
+
+	now := time.Now()
+	ms := func(t time.Time) string { return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10) }
+
+	cutoff := ms(now.Add(-t.FinishAfter))
+
+	expired, err := t.client.ZRangeByScore(ctx, journeyLastSeenKey, &redis.ZRangeBy{
+		Min: "-inf", Max: cutoff, Count: journeyReapBatch,
+	}).Result()
+
+	if err != nil {
+		return 0, err
+	}
+
+	var finished int
+	for _, journeyID := range expired {
+		ok, err := t.finish(ctx, journeyID, JourneyEndIdle, cutoff)
+		if err != nil {
+			return finished, err
+		}
+		if ok {
+			finished++
+		}
+	}
+
+	// Counts exclude journeys past the cutoff but not yet reaped
+	idleSince := ms(now.Add(-t.IdleAfter))
+
+	active, err := t.client.ZCount(ctx, journeyLastSeenKey, "("+idleSince, "+inf").Result()
+	if err != nil {
+		return finished, err
+	}
+
+	idle, err := t.client.ZCount(ctx, journeyLastSeenKey, "("+cutoff, idleSince).Result()
+	if err != nil {
+		return finished, err
+	}
+
+	journeyStates.WithLabelValues(string(JourneyActive)).Set(float64(active))
+	journeyStates.WithLabelValues(string(JourneyIdle)).Set(float64(idle))
+
+	return finished, nil
+}
+
+// Run - reaps every interval until ctx is cancelled
+func (t *JourneyTracker) Run(ctx context.Context, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := t.Reap(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Errorf("Failed to Reap Journeys: %+v", err)
+			}
+			if n > 0 {
+				log.WithFields(log.Fields{"Journeys": n}).Debug("Reaped Idle Journeys")
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package hsldatabridge
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/pquerna/ffjson/ffjson"
+)
+
+// JSONSink - Writes each event as a line of JSON, the same body as published to
+// the PUB/SUB channel. Registered as `stdout`, useful for debugging or piping
+// the decoded feed to another tool.
+type JSONSink struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+// NewJSONSink - creates a sink writing to w; w is left open on Close, the
+// caller owns it (e.g. os.Stdout, which the logger may share)
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{w: bufio.NewWriter(w)}
+}
+
+func init() {
+	RegisterSink("stdout", func(ctx context.Context, cfg *Config) (Sink, error) {
+		return NewJSONSink(os.Stdout), nil
+	})
+}
+
+// Write - buffers the encoded batch, lines are written on Flush or as the
+// buffer fills
+func (js *JSONSink) Write(ctx context.Context, batch []*EventHolder) error {
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	for _, e := range batch {
+
+		b, err := ffjson.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		if _, err := js.w.Write(b); err != nil {
+			return err
+		}
+		if err := js.w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush - writes any buffered lines
+func (js *JSONSink) Flush(ctx context.Context) error {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	return js.w.Flush()
+}
+
+// Close - flushes any buffered lines, the underlying writer is left open
+func (js *JSONSink) Close() error {
+	return js.Flush(context.Background())
+}
@@ -0,0 +1,59 @@
+package hsldatabridge
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+)
+
+// closeRecorder - a writer that records whether it was closed
+type closeRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (w *closeRecorder) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestJSONSinkCloseLeavesWriterOpen(t *testing.T) {
+
+	w := &closeRecorder{}
+	js := NewJSONSink(w)
+
+	batch := []*EventHolder{{VP: &Event{VehID: 423, RouteID: "2159"}}}
+	if err := js.Write(context.Background(), batch); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := js.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.closed {
+		t.Errorf("Close closed the underlying writer")
+	}
+
+	if lines := bytes.Count(w.Bytes(), []byte("\n")); lines != 1 {
+		t.Errorf("got %d lines after Close, want 1", lines)
+	}
+}
+
+func TestStdoutSinkCloseLeavesStdoutOpen(t *testing.T) {
+
+	sink, err := sinkFactories["stdout"](context.Background(), DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stat fails w. os.ErrClosed once the file is closed
+	if _, err := os.Stdout.Stat(); err != nil {
+		t.Errorf("os.Stdout after Close: %+v", err)
+	}
+}
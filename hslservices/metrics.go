@@ -0,0 +1,167 @@
+package hsldatabridge
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics updated in place by the workers and the redis sink, registered
+// w. RegisterMetrics
+var (
+	decodeFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "hsl_mqtt_decode_failures_total",
+		Help: "Messages that could not be decoded into a known event type, or had an invalid topic",
+	})
+
+	coercedFields = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hsl_mqtt_coerced_fields_total",
+		Help: "Fields sent w. an unexpected type and converted by the tolerant decoder, by field and variant",
+	}, []string{"field", "variant"})
+
+	pipelineDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "hsl_redis_pipeline_duration_seconds",
+		Help:    "Time to execute each Redis write pipeline",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14), // 0.5ms -> ~4s
+	})
+
+	pipelineErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "hsl_redis_pipeline_errors_total",
+		Help: "Redis write pipelines that returned an error",
+	})
+
+	newJourneys = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hsl_redis_new_journeys_total",
+		Help: "Journeys seen for the first time, i.e. timeseries created",
+	}, []string{"mode"})
+
+	journeyCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hsl_journey_cache_lookups_total",
+		Help: "Journey cache lookups by the redis sink, by result (hit, miss); only misses go to Redis",
+	}, []string{"result"})
+
+	journeysFinished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hsl_journeys_finished_total",
+		Help: "Journeys finished and cleaned up, by reason (vjout, idle)",
+	}, []string{"reason"})
+
+	journeyStates = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hsl_journeys",
+		Help: "Journeys by state (active, idle) as of the last reap",
+	}, []string{"state"})
+
+	lateSamples = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "hsl_timeseries_late_samples_total",
+		Help: "Positions written to their journey's series behind its latest position, i.e. out of order but within timeseries.lateness",
+	})
+
+	droppedSamples = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hsl_timeseries_dropped_samples_total",
+		Help: "Samples not written to a series, by reason (out_of_order: behind the journey's latest by more than timeseries.lateness, rejected: by Redis)",
+	}, []string{"reason"})
+
+	vehiclesEvicted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "hsl_vehicles_evicted_total",
+		Help: "Vehicles removed from the live vehicle index after vehicles.idle_after w.o. a position",
+	})
+
+	// NOTE: `tsi` has 1s resolution, buckets finer than that aren't useful
+	eventLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "hsl_event_latency_seconds",
+		Help:    "Time from the vehicle's timestamp (tsi) to the event's Redis write",
+		Buckets: []float64{1, 2, 3, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"mode"})
+)
+
+// brokerCollector - Reports the counters kept by the MsgBroker and Validator
+// at scrape time, rather than mirroring each update
+type brokerCollector struct {
+	mb *MsgBroker
+	v  *Validator
+
+	received  *prometheus.Desc
+	dropped   *prometheus.Desc
+	spilled   *prometheus.Desc
+	spillSize *prometheus.Desc
+	depth     *prometheus.Desc
+	capacity  *prometheus.Desc
+	rejected  *prometheus.Desc
+}
+
+func newBrokerCollector(mb *MsgBroker, v *Validator) *brokerCollector {
+	return &brokerCollector{
+		mb: mb,
+		v:  v,
+		received: prometheus.NewDesc(
+			"hsl_mqtt_received_total", "Messages received, by subscription topic filter", []string{"filter"}, nil,
+		),
+		dropped: prometheus.NewDesc(
+			"hsl_mqtt_dropped_total", "Messages dropped because StagingC was full", nil, nil,
+		),
+		spilled: prometheus.NewDesc(
+			"hsl_mqtt_spilled_total", "Messages spilled to disk because StagingC was full", nil, nil,
+		),
+		spillSize: prometheus.NewDesc(
+			"hsl_mqtt_spill_bytes", "Bytes currently held in the on-disk spill queue", nil, nil,
+		),
+		depth: prometheus.NewDesc(
+			"hsl_mqtt_staging_depth", "Messages waiting in StagingC and the per-vehicle shards", nil, nil,
+		),
+		capacity: prometheus.NewDesc(
+			"hsl_mqtt_staging_capacity", "Capacity of StagingC and the per-vehicle shards", nil, nil,
+		),
+		rejected: prometheus.NewDesc(
+			"hsl_validation_rejected_total", "Events rejected, by validation rule", []string{"rule"}, nil,
+		),
+	}
+}
+
+// Describe -
+func (c *brokerCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.received, c.dropped, c.spilled, c.spillSize, c.depth, c.capacity, c.rejected,
+	} {
+		ch <- d
+	}
+}
+
+// Collect -
+func (c *brokerCollector) Collect(ch chan<- prometheus.Metric) {
+
+	for filter, n := range c.mb.Received() {
+		ch <- prometheus.MustNewConstMetric(c.received, prometheus.CounterValue, float64(n), filter)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(c.mb.Dropped()))
+	ch <- prometheus.MustNewConstMetric(c.spilled, prometheus.CounterValue, float64(c.mb.Spilled()))
+	ch <- prometheus.MustNewConstMetric(c.depth, prometheus.GaugeValue, float64(c.mb.Staged()))
+	ch <- prometheus.MustNewConstMetric(c.capacity, prometheus.GaugeValue, float64(c.mb.StagingCapacity()))
+
+	var spillSize int64
+	if c.mb.Overflow != nil {
+		spillSize = c.mb.Overflow.Size()
+	}
+	ch <- prometheus.MustNewConstMetric(c.spillSize, prometheus.GaugeValue, float64(spillSize))
+
+	if c.v != nil {
+		for rule, n := range c.v.Rejected() {
+			ch <- prometheus.MustNewConstMetric(c.rejected, prometheus.CounterValue, float64(n), string(rule))
+		}
+	}
+}
+
+// RegisterMetrics - registers the pipeline metrics, and those kept by mb and v
+// (which may be nil), w. reg
+func RegisterMetrics(reg prometheus.Registerer, mb *MsgBroker, v *Validator) error {
+
+	for _, c := range []prometheus.Collector{
+		decodeFailures, coercedFields, pipelineDuration, pipelineErrors, eventLatency,
+		newJourneys, journeyCacheLookups, journeysFinished, journeyStates, lateSamples, droppedSamples,
+		vehiclesEvicted,
+		newBrokerCollector(mb, v),
+	} {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,7 +2,11 @@ package hsldatabridge
 
 import (
 	"fmt"
-	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/pquerna/ffjson/ffjson"
@@ -10,44 +14,211 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// Testing...
-var (
-	mqttTopic      = os.Getenv("MQTT_TOPIC")  // "/hfp/v2/journey/+/+/#"
-	mqttBrokerHost = os.Getenv("MQTT_BROKER") // "mqtt.hsl.fi"
-	mqttPort       = os.Getenv("MQTT_PORT")   // "8883"
-)
+// maxSubscribeBackoff - upper limit on the wait between retries of a failed
+// subscription
+const maxSubscribeBackoff = 60 * time.Second
+
+// Subscription - An MQTT topic filter and the QoS to subscribe at
+type Subscription struct {
+	Filter string
+	QoS    byte
+}
+
+// ParseSubscriptions - parse a comma separated list of `filter=qos` pairs, QoS
+// may be omitted and defaults to 1, e.g.
+//
+// /hfp/v2/journey/+/+/bus/#=1,/hfp/v2/journey/+/+/tram/#=0,/hfp/v2/journey/+/+/metro/#
+//
+// NOTE: Filters should not overlap, a message matching multiple filters
+// is staged once per filter
+func ParseSubscriptions(s string) ([]Subscription, error) {
+
+	var subs []Subscription
+
+	for _, entry := range strings.Split(s, ",") {
+
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		sub := Subscription{Filter: entry, QoS: 1}
+
+		if i := strings.LastIndex(entry, "="); i >= 0 {
+			qos, err := strconv.Atoi(entry[i+1:])
+			if err != nil || qos < 0 || qos > 2 {
+				return nil, fmt.Errorf("invalid QoS for topic filter %s", entry)
+			}
+			sub.Filter, sub.QoS = entry[:i], byte(qos)
+		}
+
+		subs = append(subs, sub)
+	}
+
+	if len(subs) == 0 {
+		return nil, fmt.Errorf("no topic filters in %q", s)
+	}
+
+	return subs, nil
+}
+
+// StagedMessage - An MQTT message as pushed to the staging channel, the topic
+// is kept alongside the body as the body often omits journey attributes
+type StagedMessage struct {
+	Topic    string
+	Payload  []byte
+	Received time.Time // Time the message was received from the broker
+	ack      func()
+}
+
+// Ack - acknowledges the message to the MQTT broker. No-op unless the client
+// is running w. at-least-once delivery, in which case workers must call Ack once
+// the message is written (or can never be written, e.g. invalid body)
+func (sm *StagedMessage) Ack() {
+	if sm.ack != nil {
+		sm.ack()
+	}
+}
 
 // MsgBroker ...
 // https://medium.com/swlh/golang-tips-why-pointers-to-slices-are-useful-and-how-ignoring-them-can-lead-to-tricky-bugs-cac90f72e77b
 type MsgBroker struct {
-	StagingC chan []byte
+	StagingC      chan *StagedMessage
+	Overflow      *SpillQueue           // Optional, holds messages while StagingC is full
+	Shards        []chan *StagedMessage // Optional, fed from StagingC by ShardByVehicle
+	subscriptions []Subscription
+	received      map[string]*uint64 // Messages received per topic filter
+	dropped       uint64
+	atLeastOnce   bool // Block rather than drop, workers ack each message
+
+	mu     sync.RWMutex // Held (R) by messageHandler while staging, see Close
+	closed bool
+	ackMu  sync.RWMutex // Held (R) while acking, see acker
+	gen    uint64       // Connection generation, bumped each time the connection is lost
+	armed  bool         // Set once connected, acks are dropped while the connection is down
 }
 
 // NewMsgBroker ...
 func NewMsgBroker(n int) *MsgBroker {
 	return &MsgBroker{
-		StagingC: make(chan []byte, n),
+		StagingC: make(chan *StagedMessage, n),
+		received: make(map[string]*uint64),
+	}
+}
+
+// Received - returns the count of messages received on each topic filter since
+// the client was initialized
+func (mb *MsgBroker) Received() map[string]uint64 {
+	counts := make(map[string]uint64, len(mb.received))
+	for filter, n := range mb.received {
+		counts[filter] = atomic.LoadUint64(n)
+	}
+	return counts
+}
+
+// Dropped - returns the count of messages dropped because StagingC (and
+// Overflow, if set) was full
+func (mb *MsgBroker) Dropped() uint64 {
+	return atomic.LoadUint64(&mb.dropped)
+}
+
+// Staged - returns the count of messages waiting for a worker, i.e. in StagingC
+// and in each shard
+func (mb *MsgBroker) Staged() int {
+	n := len(mb.StagingC)
+	for _, shard := range mb.Shards {
+		n += len(shard)
+	}
+	return n
+}
+
+// StagingCapacity - returns the capacity of StagingC and the shards
+func (mb *MsgBroker) StagingCapacity() int {
+	n := cap(mb.StagingC)
+	for _, shard := range mb.Shards {
+		n += cap(shard)
+	}
+	return n
+}
+
+// Spilled - returns the count of messages sent to Overflow because
+// StagingC was full
+func (mb *MsgBroker) Spilled() uint64 {
+	if mb.Overflow == nil {
+		return 0
+	}
+	return mb.Overflow.Spilled()
+}
+
+// subscriptionHandler - wraps messageHandler for a single subscription s.t.
+// throughput can be counted per topic filter
+func (mb *MsgBroker) subscriptionHandler(sub Subscription) mqtt.MessageHandler {
+	n := mb.received[sub.Filter]
+	return func(client mqtt.Client, msg mqtt.Message) {
+		atomic.AddUint64(n, 1)
+		mb.messageHandler(client, msg)
 	}
 }
 
 // messageHandler implements mqtt.PublishHandler/mqtt.MessageHandler,function passes
 // all messages along to a single staging channel
 //
-// NOTE: mqtt.messageHandler must be safe for concurrent use by multiple goroutines;
-// and should not be blocking (or call blocking code).
+// NOTE: W. at-most-once delivery the handler never blocks, the client calls it in
+// order from its router and messages are staged in the order received; from there
+// ShardByVehicle keeps each vehicle's messages in order. W. at-least-once delivery
+// the handler may block, the client calls it from a goroutine per message s.t. it
+// can't stall the network reader (and w. it, keepalives); messages are then staged
+// close to, but not strictly in, the order received
 //
-// WARNING: For this application specifically, chose to sacrifice the delivered at least
-// once property for expediency, set very short 10ms timeout  so don't launch new goroutine
-// or block for each message...
+// WARNING: By default, chose to sacrifice the delivered at least once property for
+// expediency, set very short 10ms timeout  so don't launch new goroutine or block for
+// each message... if Overflow is set, messages are spilled to disk rather than dropped.
+//
+// W. at-least-once delivery, messages are never dropped, the handler blocks until
+// StagingC has space, and acks are left to the workers (or spill, once on disk). The
+// broker limits the unacked messages in flight, so this trades throughput for delivery.
 func (mb *MsgBroker) messageHandler(client mqtt.Client, msg mqtt.Message) {
 
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
+
+	// Shutting down, w. at-least-once delivery the broker sends the message again
+	if mb.closed {
+		return
+	}
+
+	staged := &StagedMessage{Topic: msg.Topic(), Payload: msg.Payload(), Received: time.Now()}
+	if mb.atLeastOnce {
+		mb.ackMu.RLock()
+		staged.ack = mb.acker(msg, mb.gen)
+		mb.ackMu.RUnlock()
+	}
+
+	// Keep messages in order while the overflow is draining, anything that
+	// arrives while older messages are on disk goes to the back of the queue
+	if mb.Overflow != nil && mb.Overflow.Size() > 0 {
+		mb.spill(staged)
+		return
+	}
+
 	select {
-	case mb.StagingC <- msg.Payload(): // Push to staging Channel...
+	case mb.StagingC <- staged: // Push to staging Channel...
 		log.WithFields(log.Fields{
 			"Topic": msg.Topic(),
 		}).Debug("Msg Recv")
 
-	default: // Channel blocked && drop message..
+	default: // Channel blocked && spill or drop message..
+		if mb.Overflow != nil {
+			mb.spill(staged)
+			return
+		}
+
+		if mb.atLeastOnce {
+			mb.StagingC <- staged
+			return
+		}
+
+		atomic.AddUint64(&mb.dropped, 1)
 		log.WithFields(log.Fields{
 			"Topic": msg.Topic(),
 		}).Warn("Msg Recv Timeout")
@@ -55,31 +226,201 @@ func (mb *MsgBroker) messageHandler(client mqtt.Client, msg mqtt.Message) {
 
 }
 
+// acker - acks msg, received on connection generation gen, unless that connection
+// has since been lost (or closed). paho closes a connection's ack channel when it
+// goes down and panics on acks sent after; the broker re-sends the message on the
+// next session instead
+func (mb *MsgBroker) acker(msg mqtt.Message, gen uint64) func() {
+	return func() {
+		mb.ackMu.RLock()
+		defer mb.ackMu.RUnlock()
+
+		if !mb.armed || mb.gen != gen {
+			return
+		}
+
+		// NOTE: paho closes the ack channel before calling the connection lost (or
+		// reconnecting) handler, an ack sent in between still finds a closed channel
+		defer func() {
+			if r := recover(); r != nil {
+				log.WithFields(log.Fields{"Topic": msg.Topic()}).Warnf("Ack Dropped: %+v", r)
+			}
+		}()
+
+		msg.Ack()
+	}
+}
+
+// disarm - drops acks for messages received before now, until the next connection
+func (mb *MsgBroker) disarm() {
+	mb.ackMu.Lock()
+	mb.gen++
+	mb.armed = false
+	mb.ackMu.Unlock()
+}
+
+// Close - stops staging new messages; unsubscribes and disconnects client, stops
+// draining Overflow, and closes StagingC s.t. workers return once they've written
+// everything already staged. quiesce is the time allowed for in-flight work w.
+// the broker (e.g. unsubscribe, disconnect)
+func (mb *MsgBroker) Close(client mqtt.Client, quiesce time.Duration) {
+
+	// 1. Unsubscribe, no new messages are sent once the broker responds
+	filters := make([]string, len(mb.subscriptions))
+	for i, sub := range mb.subscriptions {
+		filters[i] = sub.Filter
+	}
+
+	if len(filters) > 0 {
+		if token := client.Unsubscribe(filters...); !token.WaitTimeout(quiesce) || token.Error() != nil {
+			log.WithFields(log.Fields{"Topics": filters}).Warnf("Unsubscribe Failed: %+v", token.Error())
+		}
+	}
+
+	// 2. Wait for in-flight handlers, those arriving later return w.o. staging
+	mb.mu.Lock()
+	mb.closed = true
+	mb.mu.Unlock()
+
+	// 3. Disconnect; any acks sent from here on are dropped
+	mb.disarm()
+
+	client.Disconnect(uint(quiesce.Milliseconds()))
+
+	// 4. Stop draining the overflow, then close StagingC
+	if mb.Overflow != nil {
+		if err := mb.Overflow.Close(); err != nil {
+			log.Errorf("Failed to Close Spill Queue: %+v", err)
+		}
+	}
+
+	close(mb.StagingC)
+}
+
+// spill - pushes a message to Overflow, drops the message if Overflow is full
+func (mb *MsgBroker) spill(staged *StagedMessage) {
+
+	if err := mb.Overflow.Push(staged); err != nil {
+		// NOTE: W. at-least-once delivery the message is left unacked, the broker
+		// sends it again when the session resumes
+		atomic.AddUint64(&mb.dropped, 1)
+		log.WithFields(log.Fields{
+			"Topic": staged.Topic,
+		}).Warnf("Msg Spill Failed: %+v", err)
+		return
+	}
+
+	// Once on disk the message survives a restart, safe to ack
+	staged.Ack()
+
+	log.WithFields(log.Fields{
+		"Topic": staged.Topic,
+	}).Debug("Msg Spilled")
+}
+
 // connectHandler implements mqtt.OnConnectHandler, handler logs new connections
 // to MQTT
-func connectHandler(client mqtt.Client) {
+func (mb *MsgBroker) connectHandler(client mqtt.Client) {
+
+	// Acks for messages received on this connection go through from here on
+	mb.ackMu.Lock()
+	mb.armed = true
+	mb.ackMu.Unlock()
 
 	// NOTE: For each topic, begin listening on a separate goroutine; set the
 	// subscriptions onn connection s.t if the client is disconnected, resumes
 	// previous subscriptions on reconnect...
-	go func(topic string) {
-		token := client.Subscribe(topic, 1, nil)
+	for _, sub := range mb.subscriptions {
+		go mb.subscribe(client, sub)
+	}
+}
+
+// subscribe - subscribe to a single topic filter, retrying w. backoff until the
+// broker grants the subscription or the connection is lost (in which case
+// connectHandler subscribes again on reconnect)
+func (mb *MsgBroker) subscribe(client mqtt.Client, sub Subscription) {
+
+	backoff := time.Second
+
+	for client.IsConnected() {
+
+		token := client.Subscribe(sub.Filter, sub.QoS, mb.subscriptionHandler(sub))
 		token.Wait()
-	}(mqttTopic)
 
-	log.WithFields(
-		log.Fields{"Topic": mqttTopic},
-	).Info("Subscribed to New Topic")
+		err := token.Error()
+
+		// Broker responds w. 0x80 in place of the granted QoS on failure
+		if st, ok := token.(*mqtt.SubscribeToken); ok && err == nil {
+			if qos, ok := st.Result()[sub.Filter]; ok && qos == 0x80 {
+				err = fmt.Errorf("subscription to %s refused by broker", sub.Filter)
+			}
+		}
+
+		if err == nil {
+			log.WithFields(
+				log.Fields{"Topic": sub.Filter, "QoS": sub.QoS},
+			).Info("Subscribed to New Topic")
+			return
+		}
+
+		log.WithFields(
+			log.Fields{"Topic": sub.Filter, "QoS": sub.QoS, "Retry": backoff},
+		).Errorf("Subscription Failed: %+v", err)
+
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxSubscribeBackoff {
+			backoff = maxSubscribeBackoff
+		}
+	}
 }
 
-// connectLostHandler implements mqtt.ConnectionLostHandler
-func connectionLostHandler(client mqtt.Client, err error) {
+// connectionLostHandler implements mqtt.ConnectionLostHandler, drops acks for
+// messages received on the lost connection
+//
+// NOTE: paho calls this concurrently w. the reconnect, by which point the client
+// may be connected again; reconnectingHandler has already disarmed acks then
+func (mb *MsgBroker) connectionLostHandler(client mqtt.Client, err error) {
 	log.Printf("Connect lost: %v", err)
+
+	if !client.IsConnectionOpen() {
+		mb.disarm()
+	}
+}
+
+// reconnectingHandler implements mqtt.ReconnectHandler, drops acks for messages
+// received on the lost connection; called before each reconnect attempt
+func (mb *MsgBroker) reconnectingHandler(client mqtt.Client, opts *mqtt.ClientOptions) {
+	mb.disarm()
 }
 
 // InitMQTTClient - Initializes the MQTT Client w. a fixed set of behavior
-// for onConnect, onRecv, and onDisconnect
-func InitMQTTClient(StgC *MsgBroker) *mqtt.Client {
+// for onConnect, onRecv, and onDisconnect; cfg is checked by Config.Validate
+func InitMQTTClient(cfg MQTTConfig, StgC *MsgBroker) *mqtt.Client {
+
+	subs, err := cfg.Subscriptions()
+	if err != nil {
+		log.Panic(err)
+	}
+
+	StgC.subscriptions = subs
+	for _, sub := range subs {
+		StgC.received[sub.Filter] = new(uint64)
+	}
+
+	// Set the overflow before connecting, messageHandler may be called as
+	// soon as the first subscription is granted
+	if cfg.Overflow == "spill" {
+		q, err := NewSpillQueue(cfg.SpillDir, int64(cfg.SpillMaxMB)<<20, spillSegmentBytes)
+		if err != nil {
+			log.Panic(err)
+		}
+
+		// W. at-least-once delivery a spilled message is acked, it must be on disk first
+		q.Durable = cfg.Delivery == "at-least-once"
+
+		StgC.Overflow = q
+		go q.Drain(StgC.StagingC)
+	}
 
 	// Initialize default options; instantiates a new *mqtt.ClientOptions
 	opts := mqtt.NewClientOptions()
@@ -87,13 +428,30 @@ func InitMQTTClient(StgC *MsgBroker) *mqtt.Client {
 	// Add options to `mqtt.ClientOptions`, per suggestion in lib docs, use
 	// setters rather than setting values in opts directly
 	opts.AddBroker(
-		fmt.Sprintf("mqtts://%s:%s", mqttBrokerHost, mqttPort),
+		fmt.Sprintf("mqtts://%s:%d", cfg.Broker, cfg.Port),
 	)
 
-	opts.SetOrderMatters(false)
+	// Messages are handed to messageHandler in order, it drops (or spills) rather
+	// than block
+	opts.SetOrderMatters(true)
+
+	// Persistent session; the broker holds QoS 1 messages sent while the client is
+	// down and re-sends any that weren't acked, workers ack after writing.
+	//
+	// NOTE: OrderMatters is turned off, messageHandler may block until StagingC has
+	// space; called in order it would stall the network reader until keepalives fail
+	if cfg.Delivery == "at-least-once" {
+		StgC.atLeastOnce = true
+		opts.SetClientID(cfg.ClientID)
+		opts.SetCleanSession(false)
+		opts.SetAutoAckDisabled(true)
+		opts.SetOrderMatters(false)
+	}
+
 	opts.SetDefaultPublishHandler(StgC.messageHandler)
-	opts.SetOnConnectHandler(connectHandler)
-	opts.SetConnectionLostHandler(connectionLostHandler)
+	opts.SetOnConnectHandler(StgC.connectHandler)
+	opts.SetConnectionLostHandler(StgC.connectionLostHandler)
+	opts.SetReconnectingHandler(StgC.reconnectingHandler)
 
 	// Create Client
 	client := mqtt.NewClient(opts)
@@ -102,6 +460,8 @@ func InitMQTTClient(StgC *MsgBroker) *mqtt.Client {
 		"Broker(s)":     opts.Servers,
 		"Ordered":       opts.Order,
 		"Autoreconnect": opts.AutoReconnect,
+		"CleanSession":  opts.CleanSession,
+		"Topics":        subs,
 	}).Info("New MQTT Client Connection")
 
 	// Open new connection w. Client
@@ -113,17 +473,40 @@ func InitMQTTClient(StgC *MsgBroker) *mqtt.Client {
 	return &client
 }
 
-// DeserializeMQTTBody ...
-func DeserializeMQTTBody(msgb []byte, hold *EventHolder) error {
+// DeserializeMQTTBody - Unmarshal the message body into hold and attach the parsed
+// topic, attributes missing from the body are filled from the topic. Checks on the
+// content of the event (e.g. missing coords) are left to a Validator
+func DeserializeMQTTBody(topic string, msgb []byte, hold *EventHolder) error {
 
 	// Dereference here...regret???
 	if err := ffjson.Unmarshal(msgb, &hold); err != nil {
-		return err
+
+		// Retry, converting fields the feed sends w. the wrong type; the original
+		// error is kept s.t. dead-lettered messages are grouped by its cause
+		coerced, terr := decodeTolerant(msgb, hold)
+		if terr != nil {
+			return err
+		}
+
+		hold.Coerced = coerced
+		for _, c := range coerced {
+			field := strings.SplitN(c, ":", 2)
+			coercedFields.WithLabelValues(field[0], field[1]).Inc()
+		}
 	}
 
-	if lat, lng := hold.VP.Lat, hold.VP.Lng; lat == 0.0 || lng == 0.0 {
-		return &MQTTValidationError{"Custom error; Missing coords"}
+	e := hold.Event()
+	if e == nil {
+		return &MQTTValidationError{Message: "Custom error; Unknown event type"}
 	}
 
+	t, err := ParseTopic(topic)
+	if err != nil {
+		return err
+	}
+
+	hold.Topic = t
+	e.fillFromTopic(t)
+
 	return nil
 }
@@ -0,0 +1,96 @@
+package hsldatabridge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testMessage - implements mqtt.Message for messages handed to messageHandler
+type testMessage struct {
+	topic   string
+	payload []byte
+}
+
+func (m *testMessage) Duplicate() bool   { return false }
+func (m *testMessage) Qos() byte         { return 1 }
+func (m *testMessage) Retained() bool    { return false }
+func (m *testMessage) Topic() string     { return m.topic }
+func (m *testMessage) MessageID() uint16 { return 0 }
+func (m *testMessage) Payload() []byte   { return m.payload }
+func (m *testMessage) Ack()              {}
+
+// recordingSink - a sink that keeps the timestamps written for each vehicle, in
+// the order written
+type recordingSink struct {
+	mu      sync.Mutex
+	written map[int][]int64
+}
+
+func (s *recordingSink) Write(ctx context.Context, batch []*EventHolder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range batch {
+		s.written[e.Event().VehID] = append(s.written[e.Event().VehID], e.Event().Timestamp)
+	}
+	return nil
+}
+
+func (s *recordingSink) Flush(ctx context.Context) error { return nil }
+func (s *recordingSink) Close() error                    { return nil }
+
+func TestMsgBrokerKeepsVehicleOrder(t *testing.T) {
+
+	const (
+		perVehicle = 500
+		workers    = 4
+	)
+
+	mb := NewMsgBroker(perVehicle * 2)
+	mb.Shards = NewShards(workers, 8)
+	go ShardByVehicle(mb.StagingC, mb.Shards)
+
+	sink := &recordingSink{written: make(map[int][]int64)}
+
+	var wg sync.WaitGroup
+	for _, shard := range mb.Shards {
+		wg.Add(1)
+		go func(shard chan *StagedMessage) {
+			defer wg.Done()
+			RunWorker(context.Background(), shard, []Sink{sink}, nil, nil, 16, time.Millisecond)
+		}(shard)
+	}
+
+	// Interleaved, handed over one at a time as the client does w. OrderMatters
+	vehicles := []int{423, 845}
+	for i := 0; i < perVehicle; i++ {
+		for _, veh := range vehicles {
+			mb.messageHandler(nil, &testMessage{
+				topic:   fmt.Sprintf("/hfp/v2/journey/ongoing/vp/bus/0018/%05d/2159/2/Matinkylä (M)/09:32/2442201/3/60;24/16/58/67", veh),
+				payload: []byte(fmt.Sprintf(`{"VP":{"veh":%d,"tsi":%d,"lat":60.16,"long":24.74}}`, veh, 1620979200+i)),
+			})
+		}
+	}
+
+	close(mb.StagingC)
+	wg.Wait()
+
+	if mb.Dropped() != 0 {
+		t.Fatalf("got %d dropped messages, want 0", mb.Dropped())
+	}
+
+	for _, veh := range vehicles {
+		written := sink.written[veh]
+		if len(written) != perVehicle {
+			t.Fatalf("vehicle %d: got %d events, want %d", veh, len(written), perVehicle)
+		}
+
+		for i, ts := range written {
+			if ts != int64(1620979200+i) {
+				t.Fatalf("vehicle %d: event %d has timestamp %d, want %d", veh, i, ts, 1620979200+i)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package hsldatabridge
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// OptInt - An integer field that the feed often sends as `null`, or omits; Valid
+// is false if so, s.t. a missing value isn't confused w. 0 (e.g. stop 0)
+type OptInt struct {
+	Value int
+	Valid bool
+}
+
+// MarshalJSON - encodes the value, or `null` if not set
+func (o OptInt) MarshalJSON() ([]byte, error) {
+	if !o.Valid {
+		return []byte("null"), nil
+	}
+	return strconv.AppendInt(nil, int64(o.Value), 10), nil
+}
+
+// UnmarshalJSON - decodes an integer, `null` leaves the value unset
+func (o *OptInt) UnmarshalJSON(b []byte) error {
+
+	if bytes.Equal(b, []byte("null")) {
+		*o = OptInt{}
+		return nil
+	}
+
+	i, err := strconv.Atoi(string(b))
+	if err != nil {
+		return err
+	}
+
+	*o = OptInt{Value: i, Valid: true}
+	return nil
+}
+
+// MarshalBinary - encodes the value for Redis commands, "" if not set s.t. the
+// write-behind stores NULL
+func (o OptInt) MarshalBinary() ([]byte, error) {
+	if !o.Valid {
+		return []byte{}, nil
+	}
+	return strconv.AppendInt(nil, int64(o.Value), 10), nil
+}
@@ -0,0 +1,31 @@
+package hsldatabridge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// InitPostgresClient - Opens a connection pool to PostgreSQL and confirms the
+// connection; the password is read from PGPASSWORD by pgx, same as psql
+func InitPostgresClient(ctx context.Context, cfg PostgresConfig) *pgxpool.Pool {
+
+	pool, err := pgxpool.Connect(
+		ctx, fmt.Sprintf("host=%s dbname=%s user=%s", cfg.Host, cfg.DB, cfg.User),
+	)
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Host": cfg.Host, "DB": cfg.DB,
+		}).Errorf("Invalid PostgreSQL Connection: %s", err)
+		log.Panicln(err)
+	}
+
+	log.WithFields(log.Fields{
+		"Host": cfg.Host, "DB": cfg.DB,
+	}).Info("New PostgreSQL Client Connection Established")
+
+	return pool
+}
@@ -0,0 +1,478 @@
+package hsldatabridge
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+	"github.com/pquerna/ffjson/ffjson"
+	log "github.com/sirupsen/logrus"
+)
+
+// statJourneyID checks if a journeyID already exists in the set of previously
+// seen JourneyID; attempts to SADD. Returns True if journey exists....
+func statJourneyID(ctx context.Context, client *redis.Client, key string, journeyID string) (bool, error) {
+
+	resp, err := client.Do(
+		ctx, "SADD", key, journeyID,
+	).Result()
+
+	if err != nil {
+		return false, err
+	}
+
+	// If resp == 0; then already exists...
+	return resp.(int64) == 0, nil
+}
+
+// eventHandler - writes an event of a specific type to the pipeline, see
+// `eventHandlers` for the handler used for each type
+type eventHandler func(ctx context.Context, rs *RedisSink, pipe redis.Pipeliner, e *EventHolder, journeyID string) error
+
+var eventHandlers = map[EventType]eventHandler{
+	EventTypeVP:    writePositionEvent,
+	EventTypeDUE:   writeStopEvent,
+	EventTypeARR:   writeStopEvent,
+	EventTypeDEP:   writeStopEvent,
+	EventTypeARS:   writeStopEvent,
+	EventTypePDE:   writeStopEvent,
+	EventTypePAS:   writeStopEvent,
+	EventTypeWAIT:  writeStopEvent,
+	EventTypeDOO:   writeDoorEvent,
+	EventTypeDOC:   writeDoorEvent,
+	EventTypeTLR:   writeTrafficLightEvent,
+	EventTypeTLA:   writeTrafficLightEvent,
+	EventTypeDA:    writeSignOnEvent,
+	EventTypeDOUT:  writeSignOnEvent,
+	EventTypeBA:    writeSignOnEvent,
+	EventTypeBOUT:  writeSignOnEvent,
+	EventTypeVJA:   writeSignOnEvent,
+	EventTypeVJOUT: writeSignOnEvent,
+}
+
+// eventValues - the stream fields shared by all event types, `ev` holds the
+// event type s.t. the write-behind can split the stream by type
+func eventValues(e *EventHolder, journeyID string) []interface{} {
+
+	core := e.Event()
+
+	return []interface{}{
+		"ev", string(e.Type()),
+		"rt", core.RouteID,
+		"jid", journeyID,
+		"lat", core.Lat,
+		"lng", core.Lng,
+		"time", core.Timestamp,
+		"mode", e.Topic.TransportMode,
+		"hdsg", e.Topic.Headsign,
+		"nxt", e.Topic.NextStop,
+	}
+}
+
+// writePositionEvent - writes a vehicle position (VP) to the PUB/SUB channel,
+// the events stream, and the journey's timeseries; the series must already
+// exist, see `RedisSink.registerJourney`
+func writePositionEvent(ctx context.Context, rs *RedisSink, pipe redis.Pipeliner, e *EventHolder, journeyID string) error {
+
+	// Re-encode the event w. the parsed topic attached s.t. subscribers
+	// can use mode, headsign, etc. w.o. parsing the topic themselves
+	body, err := ffjson.Marshal(e)
+
+	if err != nil {
+		return err
+	}
+
+	// 1. Publish full body...
+	pipe.Publish(
+		ctx, "currentLocationsPS", body,
+	)
+
+	// 2. XADD the full event body to a stream of events, these are read
+	// through a consumer group by cmd/writebehind (see StreamConsumer) and
+	// written behind to PostgreSQL
+	pipe.XAdd(
+		ctx, &redis.XAddArgs{
+			Stream: "events",
+			Values: append(
+				eventValues(e, journeyID),
+				"spd", e.VP.Spd,
+				"acc", e.VP.Acc,
+				"dl", e.VP.DeltaToSchedule,
+			),
+		},
+	)
+
+	// 3. TS.ADD a series of statistics to the timeseries created
+	// by `createJourneySeries`, at the vehicle's timestamp
+	ts := vehicleMillis(e)
+	behind := time.Duration(rs.journeys.Observe(journeyID, ts)-ts) * time.Millisecond
+
+	if rs.acceptSample(journeyID, behind) {
+		addJourneySamples(ctx, pipe, rs.TimeSeries.Series, e.VP, journeyID, ts)
+	}
+
+	// 4. Set the vehicle's live state, unless the journey already has a
+	// newer position
+	if behind <= 0 {
+		rs.vehicles.Update(ctx, pipe, e, journeyID, ts)
+	}
+
+	return nil
+}
+
+// journeySample - a position of a journey, at the vehicle's timestamp (unix ms)
+type journeySample struct {
+	journeyID string
+	ts        int64
+}
+
+// vehicleMillis - the event's timestamp (unix ms), or the current time if the
+// vehicle sent neither `tst` nor `tsi`
+func vehicleMillis(e *EventHolder) int64 {
+	if ms := e.Event().Millis(); ms > 0 {
+		return ms
+	}
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// acceptSample - reports whether a position behind the latest position of the journey
+// (see JourneyCache.Observe) is written to the journey's series, i.e. it's no more than
+// TimeSeries.Lateness behind. Positions are still published && streamed either way
+func (rs *RedisSink) acceptSample(journeyID string, behind time.Duration) bool {
+	switch {
+	case behind <= 0:
+		return true
+	case behind <= rs.TimeSeries.Lateness:
+		lateSamples.Inc()
+		return true
+	default:
+		droppedSamples.WithLabelValues("out_of_order").Inc()
+		log.WithFields(
+			log.Fields{"JourneyID": journeyID, "Behind": behind},
+		).Debug("Dropped Out of Order Sample")
+		return false
+	}
+}
+
+// replyErr - returns the first error in the replies to a pipeline, other than a
+// TS.ADD rejected by Redis (e.g. a sample older than the series' retention); these
+// are counted s.t. one bad sample doesn't fail (and retry) the whole batch
+func replyErr(cmds []redis.Cmder) error {
+
+	for _, cmd := range cmds {
+		err := cmd.Err()
+		if err == nil {
+			continue
+		}
+
+		if _, ok := err.(redis.Error); ok && cmd.Name() == "ts.add" {
+			droppedSamples.WithLabelValues("rejected").Inc()
+			log.WithFields(
+				log.Fields{"Args": cmd.Args()[1:3]},
+			).Debugf("TS.ADD Rejected: %+v", err)
+			continue
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// writeStopEvent - writes arrivals, departures, etc. at a stop to the events stream
+func writeStopEvent(ctx context.Context, rs *RedisSink, pipe redis.Pipeliner, e *EventHolder, journeyID string) error {
+
+	s := e.StopEvent()
+
+	pipe.XAdd(
+		ctx, &redis.XAddArgs{
+			Stream: "events",
+			Values: append(
+				eventValues(e, journeyID),
+				"stop", s.Stop,
+				"dl", s.DeltaToSchedule,
+				"ttarr", s.ScheduledArrival,
+				"ttdep", s.ScheduledDeparture,
+			),
+		},
+	)
+
+	return nil
+}
+
+// writeDoorEvent - writes door open/close events to the events stream
+func writeDoorEvent(ctx context.Context, rs *RedisSink, pipe redis.Pipeliner, e *EventHolder, journeyID string) error {
+
+	d := e.DoorEvent()
+
+	pipe.XAdd(
+		ctx, &redis.XAddArgs{
+			Stream: "events",
+			Values: append(
+				eventValues(e, journeyID),
+				"stop", d.Stop,
+				"drst", d.DoorStatus,
+			),
+		},
+	)
+
+	return nil
+}
+
+// writeTrafficLightEvent - writes traffic light priority requests and
+// acknowledgements to the events stream
+func writeTrafficLightEvent(ctx context.Context, rs *RedisSink, pipe redis.Pipeliner, e *EventHolder, journeyID string) error {
+
+	t := e.TrafficLightEvent()
+
+	pipe.XAdd(
+		ctx, &redis.XAddArgs{
+			Stream: "events",
+			Values: append(
+				eventValues(e, journeyID),
+				"tlpreq", t.RequestID,
+				"tlptype", t.RequestType,
+				"tlpprio", t.PriorityLevel,
+				"tlpreason", t.Reason,
+				"tlpatt", t.AttemptSeq,
+				"tlpdec", t.Decision,
+				"sid", t.JunctionID,
+				"sgid", t.SignalGroupID,
+			),
+		},
+	)
+
+	return nil
+}
+
+// writeSignOnEvent - writes driver, block and journey sign on/off events to
+// the events stream
+func writeSignOnEvent(ctx context.Context, rs *RedisSink, pipe redis.Pipeliner, e *EventHolder, journeyID string) error {
+
+	d := e.SignOnEvent()
+
+	pipe.XAdd(
+		ctx, &redis.XAddArgs{
+			Stream: "events",
+			Values: append(
+				eventValues(e, journeyID),
+				"drtype", d.DriverType,
+			),
+		},
+	)
+
+	return nil
+}
+
+// RedisSink - Writes events to the Redis PUB/SUB channel, events stream and journey
+// timeseries; each event type is written by its own handler, see `eventHandlers`
+type RedisSink struct {
+	// Atomic - wraps each batch in MULTI/EXEC; w.o. it other clients may
+	// see part of a batch before the rest is written
+	Atomic bool
+
+	// TimeSeries - the timeseries created for each journey && fed by its
+	// positions, and their rollups
+	TimeSeries TimeSeriesConfig
+
+	client   *redis.Client
+	journeys *JourneyCache
+	tracker  *JourneyTracker
+	vehicles *VehicleStore
+
+	stopReaper context.CancelFunc
+	reapers    sync.WaitGroup
+}
+
+// NewRedisSink - creates a sink writing w. client, the sink takes ownership of
+// the client and closes it on Close. Journeys in the cache are assumed to have
+// their timeseries created already
+func NewRedisSink(client *redis.Client, journeys *JourneyCache, tracker *JourneyTracker, vehicles *VehicleStore) *RedisSink {
+	return &RedisSink{
+		Atomic: true, TimeSeries: DefaultConfig().TimeSeries,
+		client: client, journeys: journeys, tracker: tracker, vehicles: vehicles,
+	}
+}
+
+func init() {
+	RegisterSink("redis", func(ctx context.Context, cfg *Config) (Sink, error) {
+
+		client := InitRedisClient(ctx, cfg.Redis)
+		journeys := NewJourneyCache(cfg.Redis.JourneyCacheSize, cfg.Redis.JourneyCacheTTL)
+
+		rs := NewRedisSink(
+			client, journeys, NewJourneyTracker(client, journeys, cfg.Journeys), NewVehicleStore(client, cfg.Vehicles),
+		)
+		rs.Atomic = cfg.Redis.Pipeline == "tx"
+		rs.TimeSeries = cfg.TimeSeries
+		rs.tracker.Series = cfg.TimeSeries.Series
+		rs.StartReaper(cfg.Journeys.ReapInterval)
+
+		return rs, nil
+	})
+}
+
+// StartReaper - reaps idle journeys, and evicts stale vehicles, every interval until
+// the sink is closed, see JourneyTracker.Reap && VehicleStore.Evict
+func (rs *RedisSink) StartReaper(interval time.Duration) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rs.stopReaper = cancel
+
+	rs.reapers.Add(2)
+	go func() {
+		defer rs.reapers.Done()
+		rs.tracker.Run(ctx, interval)
+	}()
+
+	go func() {
+		defer rs.reapers.Done()
+		rs.vehicles.Run(ctx, interval)
+	}()
+}
+
+// registerJourney - adds the journey to the set of seen journeys and, if it wasn't
+// already there, creates its timeseries. SADD decides which of several processes
+// creates the series, JourneyCache.Ensure which of several workers
+func (rs *RedisSink) registerJourney(ctx context.Context, e *EventHolder, journeyID string) error {
+
+	journeyExists, err := statJourneyID(ctx, rs.client, journeySetKey, journeyID)
+	if err != nil {
+		return err
+	}
+
+	if journeyExists {
+		return nil
+	}
+
+	log.WithFields(
+		log.Fields{
+			"JourneyID": journeyID,
+		},
+	).Info("New Journey Registered")
+	newJourneys.WithLabelValues(e.Topic.TransportMode).Inc()
+
+	createJourneySeries(ctx, rs.client, rs.TimeSeries.Series, journeyID, e.Topic.TransportMode)
+
+	return nil
+}
+
+// Write - writes the batch to Redis using a single pipeline (a Tx pipeline if
+// Atomic), cuts back on some network round-trip; each event type adds its own
+// commands
+func (rs *RedisSink) Write(ctx context.Context, batch []*EventHolder) error {
+
+	var pipe redis.Pipeliner
+	if rs.Atomic {
+		pipe = rs.client.TxPipeline()
+	} else {
+		pipe = rs.client.Pipeline()
+	}
+
+	rollups := rollupBatch{}
+	rolledUp := make(map[journeySample]bool)
+
+	for _, e := range batch {
+
+		// Main procedure for adding a series keys, values to the redis
+		// instance
+		journeyID := e.Event().GetEventHash()
+
+		// Only journeys missing from the cache go to Redis, the positions are
+		// still written if registration fails; TS.ADD creates the series w.o.
+		// the compaction rule
+		if e.Type() == EventTypeVP {
+			err := rs.journeys.Ensure(journeyID, func() error {
+				return rs.registerJourney(ctx, e, journeyID)
+			})
+
+			if err != nil {
+				log.WithFields(
+					log.Fields{"JourneyID": journeyID},
+				).Warnf("Failed to Register Journey: %+v", err)
+			}
+
+			rs.tracker.Seen(ctx, pipe, journeyID, time.Now())
+
+			// Rollups are summed (ON_DUPLICATE SUM), a redelivered position would
+			// be counted twice; see JourneyCache.Duplicate for what's caught
+			s := journeySample{journeyID, vehicleMillis(e)}
+			if !rolledUp[s] && !rs.journeys.Duplicate(journeyID, s.ts) {
+				rollups.add(&rs.TimeSeries, e, time.Unix(0, s.ts*int64(time.Millisecond)))
+				rolledUp[s] = true
+			}
+		}
+
+		if err := eventHandlers[e.Type()](ctx, rs, pipe, e, journeyID); err != nil {
+			log.WithFields(
+				log.Fields{"Topic": e.Topic},
+			).Errorf("Failed to Encode Event: %+v", err)
+		}
+	}
+
+	// Network-wide rollups, one TS.ADD per series && bucket for the whole batch
+	rollups.write(ctx, pipe, rs.TimeSeries.RollupRetention)
+
+	// Execute Pipe!
+	start := time.Now()
+	cmds, err := pipe.Exec(ctx)
+	pipelineDuration.Observe(time.Since(start).Seconds())
+
+	// Redis replied to each command, check which failed
+	if _, ok := err.(redis.Error); ok {
+		err = replyErr(cmds)
+	}
+
+	if err != nil {
+		pipelineErrors.Inc()
+
+		if err, ok := err.(net.Error); ok {
+			log.Errorf("Redis Down: %+v", err)
+		}
+
+		return err
+	}
+
+	for s := range rolledUp {
+		rs.journeys.Written(s.journeyID, s.ts)
+	}
+
+	// End-to-end latency; `tsi` is in seconds
+	for _, e := range batch {
+		if tsi := e.Event().Timestamp; tsi > 0 {
+			eventLatency.WithLabelValues(e.Topic.TransportMode).Observe(time.Since(time.Unix(tsi, 0)).Seconds())
+		}
+	}
+
+	// Vehicle signed out of the journey, once the VJOUT itself is written
+	for _, e := range batch {
+		if e.Type() == EventTypeVJOUT {
+			journeyID := e.Event().GetEventHash()
+			if _, err := rs.tracker.Finish(ctx, journeyID, JourneyEndSignOut); err != nil {
+				log.WithFields(
+					log.Fields{"JourneyID": journeyID},
+				).Errorf("Failed to Finish Journey: %+v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Flush - no-op, each call to Write executes its own pipeline
+func (rs *RedisSink) Flush(ctx context.Context) error {
+	return nil
+}
+
+// Close - stops the reapers, if started, and closes the Redis client
+func (rs *RedisSink) Close() error {
+
+	if rs.stopReaper != nil {
+		rs.stopReaper()
+		rs.reapers.Wait()
+	}
+
+	return rs.client.Close()
+}
@@ -0,0 +1,155 @@
+package hsldatabridge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+)
+
+// benchVehicles - vehicles (each on its own journey) the benchmark events cycle through
+const benchVehicles = 500
+
+// benchRedisClient - connects to the Redis set by REDIS_HOST, REDIS_PORT, && REDIS_DB
+// (see Config), skips the benchmark if it's down or lacks RedisTimeSeries.
+//
+// NOTE: Writes journeys, series, and the events stream; use a scratch Redis
+func benchRedisClient(b *testing.B) *redis.Client {
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		b.Skipf("Redis unavailable: %+v", err)
+	}
+
+	if err := client.Do(ctx, "TS.INFO", "hsl:bench:probe").Err(); err != nil && strings.HasPrefix(err.Error(), "ERR unknown command") {
+		client.Close()
+		b.Skip("Redis has no RedisTimeSeries module")
+	}
+
+	return client
+}
+
+// redisCPU - returns the CPU time (sys + user) used by the Redis server so far
+func redisCPU(b *testing.B, client *redis.Client) time.Duration {
+
+	info, err := client.Info(context.Background(), "cpu").Result()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var secs float64
+	for _, line := range strings.Split(info, "\r\n") {
+		if kv := strings.SplitN(line, ":", 2); len(kv) == 2 && (kv[0] == "used_cpu_sys" || kv[0] == "used_cpu_user") {
+			f, _ := strconv.ParseFloat(kv[1], 64)
+			secs += f
+		}
+	}
+
+	return time.Duration(secs * float64(time.Second))
+}
+
+// benchPosition - the i-th position of the benchmark, vehicles take turns s.t. each
+// journey's positions are 1s apart
+func benchPosition(start time.Time, i int) *EventHolder {
+
+	veh := i % benchVehicles
+	ts := start.Add(time.Duration(i/benchVehicles) * time.Second)
+
+	topic := fmt.Sprintf(
+		"/hfp/v2/journey/ongoing/vp/bus/0018/%05d/2159/2/Matinkylä (M)/09:32/2442201/3/60;24/16/58/67", veh,
+	)
+
+	t, _ := ParseTopic(topic)
+
+	return &EventHolder{
+		VP: &Event{
+			JrnID: veh, ODay: "2021-05-14", Direction: "2", VehID: veh, RouteID: "2159", Start: "09:32",
+			Timestamp: ts.Unix(), Tst: ts.Format(time.RFC3339Nano),
+			Lat: 60.16 + float64(veh)*1e-4, Lng: 24.74, Heading: 90, Spd: 8.5, Acc: 0.1, DeltaToSchedule: -12, Occupancy: 0,
+		},
+		Topic: t,
+	}
+}
+
+// BenchmarkRedisSinkWrite - compares one MULTI/EXEC per event (the connector before
+// batching) w. batches of 100 written in a MULTI/EXEC (`redis.pipeline: tx`) and as
+// a plain pipeline (`redis.pipeline: plain`). Reports events/s && the Redis server's
+// CPU time per event, e.g.
+//
+//	REDIS_HOST=localhost go test -run ^$ -bench RedisSinkWrite -benchtime 20000x
+func BenchmarkRedisSinkWrite(b *testing.B) {
+
+	for _, bc := range []struct {
+		name      string
+		batchSize int
+		atomic    bool
+	}{
+		{"per-event/tx", 1, true},
+		{"batch-100/tx", 100, true},
+		{"batch-100/plain", 100, false},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+
+			client := benchRedisClient(b)
+			ctx := context.Background()
+
+			cfg := DefaultConfig()
+			journeys := NewJourneyCache(cfg.Redis.JourneyCacheSize, cfg.Redis.JourneyCacheTTL)
+			rs := NewRedisSink(client, journeys, NewJourneyTracker(client, journeys, cfg.Journeys), NewVehicleStore(client, cfg.Vehicles))
+			rs.Atomic = bc.atomic
+			defer rs.Close()
+
+			// Positions start after any written by a previous run, s.t. none are out of order
+			start := time.Now().Add(time.Duration(b.N/benchVehicles+1) * time.Second).Truncate(time.Second)
+
+			// Register every journey first, s.t. each case measures writes to known
+			// journeys rather than whichever runs first creating their series
+			warm := make([]*EventHolder, benchVehicles)
+			for i := range warm {
+				warm[i] = benchPosition(start.Add(-time.Second), i)
+			}
+			if err := rs.Write(ctx, warm); err != nil {
+				b.Fatal(err)
+			}
+
+			events := make([]*EventHolder, b.N)
+			for i := range events {
+				events[i] = benchPosition(start, i)
+			}
+
+			cpu, began := redisCPU(b, client), time.Now()
+			b.ResetTimer()
+
+			for i := 0; i < len(events); i += bc.batchSize {
+				end := i + bc.batchSize
+				if end > len(events) {
+					end = len(events)
+				}
+				if err := rs.Write(ctx, events[i:end]); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.StopTimer()
+
+			b.ReportMetric(float64(b.N)/time.Since(began).Seconds(), "events/s")
+			b.ReportMetric(float64((redisCPU(b, client)-cpu).Microseconds())/float64(b.N), "redis-cpu-µs/event")
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package hsldatabridge
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+)
+
+// RollupDims - the dimensions positions can be rolled up by, e.g. all buses on
+// route 550; each returns "" if the event has no value for the dimension
+var RollupDims = map[string]func(e *EventHolder) string{
+	"route": func(e *EventHolder) string { return e.Event().RouteID },
+	"mode":  func(e *EventHolder) string { return e.Topic.TransportMode },
+	"oper":  func(e *EventHolder) string { return e.Topic.OperatorID },
+}
+
+// rollupKey - identifies a rollup series and bucket, e.g. the delay of the vehicles
+// on route 550, run by operator 0018, in the minute from 08:00
+type rollupKey struct {
+	series string
+	cell   string // The event's value of each dimension, e.g. `route=550,mode=bus,oper=0018`
+	bucket int64  // Start of the bucket, unix ms
+}
+
+type rollupSum struct {
+	sum float64
+	n   int
+}
+
+// rollupBatch - sums the samples of a batch of positions by rollup series and bucket
+// s.t. each is written w. a single TS.ADD
+type rollupBatch map[rollupKey]*rollupSum
+
+// add - adds the position to the rollups of each series w. Rollup set, in the cell
+// of its values of each dimension
+func (rb rollupBatch) add(cfg *TimeSeriesConfig, e *EventHolder, at time.Time) {
+
+	bucket := at.Truncate(cfg.RollupBucket).UnixNano() / int64(time.Millisecond)
+
+	cell := rollupCell(cfg.rollupDims(), e)
+	if cell == "" {
+		return
+	}
+
+	for i := range cfg.Series {
+		s := &cfg.Series[i]
+		if !s.Rollup {
+			continue
+		}
+
+		value, ok := seriesFields[s.Field](e.Event())
+		if !ok {
+			continue
+		}
+
+		f, ok := toFloat(value)
+		if !ok {
+			continue
+		}
+
+		k := rollupKey{series: s.Name, cell: cell, bucket: bucket}
+		if rb[k] == nil {
+			rb[k] = &rollupSum{}
+		}
+
+		rb[k].sum += f
+		rb[k].n++
+	}
+}
+
+// rollupCell - returns the event's value of each dimension, e.g. `route=550,mode=bus`;
+// dimensions the event has no value for are left out
+func rollupCell(dims []string, e *EventHolder) string {
+
+	var pairs []string
+	for _, dim := range dims {
+		if v := RollupDims[dim](e); v != "" {
+			pairs = append(pairs, dim+"="+v)
+		}
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// write - adds the sums && counts to the pipeline. Samples are written at the start
+// of their bucket w. ON_DUPLICATE SUM, s.t. batches from several workers (or
+// connectors) add up; series are created by the first TS.ADD.
+//
+// Each cell's series is labelled w. its value of each dimension, s.t. a dimension's
+// rollups are summed over cells by TS.MRANGE ... GROUPBY <dim> REDUCE SUM
+func (rb rollupBatch) write(ctx context.Context, pipe redis.Pipeliner, retention time.Duration) {
+	for k, s := range rb {
+		labels := []interface{}{"LABELS", "rollup", k.series}
+		for _, pair := range strings.Split(k.cell, ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			labels = append(labels, kv[0], kv[1])
+		}
+
+		for stat, value := range map[string]float64{"sum": s.sum, "count": float64(s.n)} {
+			args := []interface{}{
+				"TS.ADD", RollupKey(k.series, k.cell, stat), k.bucket, value,
+				"RETENTION", retention.Milliseconds(), "ON_DUPLICATE", "SUM",
+			}
+			pipe.Do(ctx, append(append(args, labels...), "stat", stat)...)
+		}
+	}
+}
+
+// RollupKey - returns the key of a rollup series for a cell, e.g.
+// `rollup:delay:route=550,mode=bus,oper=0018:sum`; stat is `sum` or `count`
+func RollupKey(series, cell, stat string) string {
+	return fmt.Sprintf("rollup:%s:%s:%s", series, cell, stat)
+}
+
+// toFloat - converts the values returned by seriesFields
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// RollupPoint - The mean of a series over a bucket, and the count of samples
+type RollupPoint struct {
+	Timestamp int64   `json:"ts"` // Start of the bucket, unix ms
+	Mean      float64 `json:"mean"`
+	Count     int64   `json:"n"`
+}
+
+// Rollup - The rollup of a series for one value of a dimension, e.g. delay on
+// route 550
+type Rollup struct {
+	Dim    string        `json:"dim"`
+	Value  string        `json:"value"`
+	Series string        `json:"series"`
+	Points []RollupPoint `json:"points"`
+}
+
+// QueryRollups - returns the rollups of series by dim, for every value of dim (or only
+// value, if set), re-bucketed to bucket, since from. The sum && count series of every
+// cell are summed by dim in Redis, w. TS.MRANGE ... GROUPBY dim REDUCE SUM, and
+// divided per bucket
+func QueryRollups(ctx context.Context, client *redis.Client, dim, value, series string, from time.Time, bucket time.Duration) ([]*Rollup, error) {
+
+	rollups := make(map[string]*Rollup)
+	points := make(map[string]map[int64]*RollupPoint)
+
+	for _, stat := range []string{"sum", "count"} {
+
+		// NOTE: `dim!=` matches series w. any value of dim, cells w.o. one are left out
+		filter := []interface{}{"rollup=" + series, "stat=" + stat, dim + "!="}
+		if value != "" {
+			filter = append(filter, fmt.Sprintf("%s=%s", dim, value))
+		}
+
+		args := append([]interface{}{
+			"TS.MRANGE", from.UnixNano() / int64(time.Millisecond), "+",
+			"AGGREGATION", "SUM", bucket.Milliseconds(), "FILTER",
+		}, filter...)
+
+		result, err := client.Do(ctx, append(args, "GROUPBY", dim, "REDUCE", "SUM")...).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		groups, err := parseGroupedMRange(result, dim)
+		if err != nil {
+			return nil, err
+		}
+
+		for v, samples := range groups {
+
+			if rollups[v] == nil {
+				rollups[v] = &Rollup{Dim: dim, Value: v, Series: series}
+				points[v] = make(map[int64]*RollupPoint)
+			}
+
+			for _, sample := range samples {
+
+				p := points[v][sample.ts]
+				if p == nil {
+					p = &RollupPoint{Timestamp: sample.ts}
+					points[v][sample.ts] = p
+				}
+
+				if stat == "sum" {
+					p.Mean = sample.value
+				} else {
+					p.Count = int64(sample.value)
+				}
+			}
+		}
+	}
+
+	resp := make([]*Rollup, 0, len(rollups))
+
+	for v, r := range rollups {
+		for _, p := range points[v] {
+			if p.Count == 0 {
+				continue
+			}
+			p.Mean /= float64(p.Count)
+			r.Points = append(r.Points, *p)
+		}
+
+		sort.Slice(r.Points, func(i, j int) bool { return r.Points[i].Timestamp < r.Points[j].Timestamp })
+		resp = append(resp, r)
+	}
+
+	sort.Slice(resp, func(i, j int) bool { return resp[i].Value < resp[j].Value })
+	return resp, nil
+}
+
+// rollupSample - A sample of a TS.MRANGE reply
+type rollupSample struct {
+	ts    int64 // Unix ms
+	value float64
+}
+
+// parseGroupedMRange - parses the reply to TS.MRANGE ... GROUPBY dim, returns the
+// samples of each value of dim. Each group is [`<dim>=<value>`, [labels], [[ts, value], ...]]
+func parseGroupedMRange(reply interface{}, dim string) (map[string][]rollupSample, error) {
+
+	groups, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected TS.MRANGE reply %v", reply)
+	}
+
+	parsed := make(map[string][]rollupSample, len(groups))
+
+	for _, group := range groups {
+
+		g, ok := group.([]interface{})
+		if !ok || len(g) < 3 {
+			return nil, fmt.Errorf("unexpected TS.MRANGE group %v", group)
+		}
+
+		key, ok := g[0].(string)
+		if !ok || !strings.HasPrefix(key, dim+"=") {
+			return nil, fmt.Errorf("unexpected TS.MRANGE group %v, want %s=<value>", g[0], dim)
+		}
+
+		samples, ok := g[2].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected samples for %s: %v", key, g[2])
+		}
+
+		v := strings.TrimPrefix(key, dim+"=")
+		parsed[v] = make([]rollupSample, 0, len(samples))
+
+		for _, sample := range samples {
+
+			pair, ok := sample.([]interface{})
+			if !ok || len(pair) < 2 {
+				return nil, fmt.Errorf("unexpected sample for %s: %v", key, sample)
+			}
+
+			ts, ok := pair[0].(int64)
+			if !ok {
+				return nil, fmt.Errorf("unexpected timestamp for %s: %v", key, pair[0])
+			}
+
+			s, ok := pair[1].(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected value for %s: %v", key, pair[1])
+			}
+
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			parsed[v] = append(parsed[v], rollupSample{ts: ts, value: f})
+		}
+	}
+
+	return parsed, nil
+}
+
+// rollupDims - the dimensions in RollupBy
+func (c *TimeSeriesConfig) rollupDims() []string {
+
+	var dims []string
+	for _, d := range strings.Split(c.RollupBy, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			dims = append(dims, d)
+		}
+	}
+
+	return dims
+}
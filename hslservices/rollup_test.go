@@ -0,0 +1,60 @@
+package hsldatabridge
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGroupedMRange(t *testing.T) {
+
+	// As returned for TS.MRANGE ... AGGREGATION SUM 60000 FILTER rollup=delay stat=sum mode!= GROUPBY mode REDUCE SUM
+	reply := []interface{}{
+		[]interface{}{
+			"mode=bus",
+			[]interface{}{
+				[]interface{}{"mode", "bus"},
+				[]interface{}{"__reducer__", "sum"},
+				[]interface{}{"__source__", "rollup:delay:route=550,mode=bus,oper=0018:sum,rollup:delay:route=2159,mode=bus,oper=0018:sum"},
+			},
+			[]interface{}{
+				[]interface{}{int64(1620979200000), "-312"},
+				[]interface{}{int64(1620979260000), "45.5"},
+			},
+		},
+		[]interface{}{"mode=tram", []interface{}{}, []interface{}{}},
+	}
+
+	got, err := parseGroupedMRange(reply, "mode")
+	if err != nil {
+		t.Fatalf("parseGroupedMRange: %+v", err)
+	}
+
+	want := map[string][]rollupSample{
+		"bus":  {{ts: 1620979200000, value: -312}, {ts: 1620979260000, value: 45.5}},
+		"tram": {},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGroupedMRange\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestParseGroupedMRangeInvalid(t *testing.T) {
+
+	for _, tc := range []struct {
+		name  string
+		reply interface{}
+	}{
+		{"not an array", "OK"},
+		{"short group", []interface{}{[]interface{}{"mode=bus", []interface{}{}}}},
+		{"ungrouped, i.e. w.o. GROUPBY", []interface{}{[]interface{}{"rollup:delay:mode=bus:sum", []interface{}{}, []interface{}{}}}},
+		{"samples not an array", []interface{}{[]interface{}{"mode=bus", []interface{}{}, nil}}},
+		{"short sample", []interface{}{[]interface{}{"mode=bus", []interface{}{}, []interface{}{[]interface{}{int64(1)}}}}},
+		{"string timestamp", []interface{}{[]interface{}{"mode=bus", []interface{}{}, []interface{}{[]interface{}{"1", "2"}}}}},
+		{"bad value", []interface{}{[]interface{}{"mode=bus", []interface{}{}, []interface{}{[]interface{}{int64(1), "nan?"}}}}},
+	} {
+		if _, err := parseGroupedMRange(tc.reply, "mode"); err == nil {
+			t.Errorf("parseGroupedMRange w. %s: got nil error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package hsldatabridge
+
+import (
+	"hash/fnv"
+	"strings"
+)
+
+// VehicleKey - returns the operator and vehicle number from a HFP v2 topic, e.g.
+// `0018/00423`, or "" if the topic is too short; used w.o. parsing the full topic
+func VehicleKey(topic string) string {
+
+	// NOTE: topic begins w. a leading `/`, levels[7:9] are the operator && vehicle
+	levels := strings.SplitN(topic, "/", 10)
+	if len(levels) < 9 {
+		return ""
+	}
+
+	return levels[7] + "/" + levels[8]
+}
+
+// NewShards - creates n shard channels, each buffering up to size messages
+func NewShards(n int, size int) []chan *StagedMessage {
+	shards := make([]chan *StagedMessage, n)
+	for i := range shards {
+		shards[i] = make(chan *StagedMessage, size)
+	}
+	return shards
+}
+
+// ShardByVehicle - routes each message from C to a shard by its vehicle, s.t. all
+// messages from a vehicle go to the same worker in the order they were staged;
+// closes the shards once C is closed. Run one worker per shard.
+//
+// NOTE: A full shard blocks the rest, StagingC then fills and the broker drops
+// (or spills) messages as it would if every worker were behind
+func ShardByVehicle(C <-chan *StagedMessage, shards []chan *StagedMessage) {
+
+	defer func() {
+		for _, shard := range shards {
+			close(shard)
+		}
+	}()
+
+	for msg := range C {
+		h := fnv.New32a()
+		h.Write([]byte(VehicleKey(msg.Topic)))
+
+		shards[h.Sum32()%uint32(len(shards))] <- msg
+	}
+}
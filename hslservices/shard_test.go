@@ -0,0 +1,79 @@
+package hsldatabridge
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestVehicleKey(t *testing.T) {
+
+	for topic, want := range map[string]string{
+		"/hfp/v2/journey/ongoing/vp/bus/0018/00423/2159/2/Matinkylä (M)/09:32/2442201/3/60;24/16/58/67": "0018/00423",
+		"/hfp/v2/deadrun/upcoming/da/ubus/0012/01502":                                                   "0012/01502",
+		"/hfp/v2/journey/ongoing/vp/bus/0018":                                                           "",
+		"":                                                                                              "",
+	} {
+		if got := VehicleKey(topic); got != want {
+			t.Errorf("VehicleKey(%q): got %q, want %q", topic, got, want)
+		}
+	}
+}
+
+func TestShardByVehicle(t *testing.T) {
+
+	const workers = 4
+
+	C := make(chan *StagedMessage, 100)
+	shards := NewShards(workers, 100)
+
+	topic := func(veh int, route string) string {
+		return fmt.Sprintf("/hfp/v2/journey/ongoing/vp/bus/0018/%05d/%s/2/Matinkylä (M)/09:32/2442201/3/60;24/16/58/67", veh, route)
+	}
+
+	// Each vehicle several times, on different routes
+	for i := 0; i < 3; i++ {
+		for veh := 1; veh <= 20; veh++ {
+			C <- &StagedMessage{Topic: topic(veh, fmt.Sprint(2159+i))}
+		}
+	}
+
+	close(C)
+
+	done := make(chan struct{})
+	go func() {
+		ShardByVehicle(C, shards)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ShardByVehicle didn't return once C was closed")
+	}
+
+	shardOf := make(map[string]int)
+	used := make(map[int]bool)
+
+	for i, shard := range shards {
+
+		// Closed once C is closed, i.e. the range ends
+		for msg := range shard {
+			key := VehicleKey(msg.Topic)
+			if prev, ok := shardOf[key]; ok && prev != i {
+				t.Errorf("vehicle %s routed to shards %d and %d", key, prev, i)
+			}
+			shardOf[key] = i
+			used[i] = true
+		}
+	}
+
+	if len(shardOf) != 20 {
+		t.Errorf("got %d vehicles, want 20", len(shardOf))
+	}
+
+	// 20 vehicles over 4 shards, the work is spread
+	if len(used) < 2 {
+		t.Errorf("got %d shards in use, want at least 2", len(used))
+	}
+}
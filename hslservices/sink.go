@@ -0,0 +1,96 @@
+package hsldatabridge
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sink - An output of the ingestion pipeline. Workers decode each staged message
+// and write it to every configured sink, see `RunWorker`.
+//
+// NOTE: Sinks must be safe for concurrent use, all workers share the same
+// set of sinks
+type Sink interface {
+	// Write - writes a batch of decoded events; sinks may buffer writes until
+	// the next call to Flush
+	Write(ctx context.Context, batch []*EventHolder) error
+
+	// Flush - writes anything buffered by the sink
+	Flush(ctx context.Context) error
+
+	// Close - flushes and releases any connections held by the sink
+	Close() error
+}
+
+// SinkFactory - opens a new sink w. the settings in cfg, see `RegisterSink`
+type SinkFactory func(ctx context.Context, cfg *Config) (Sink, error)
+
+var sinkFactories = map[string]SinkFactory{}
+
+// RegisterSink - makes a sink available by name to OpenSinks, sinks register
+// themselves from init()
+func RegisterSink(name string, factory SinkFactory) {
+	sinkFactories[name] = factory
+}
+
+// OpenSinks - opens the sinks named in a comma separated list, e.g. `redis,stdout`;
+// closes any sinks already opened if one fails to open
+func OpenSinks(ctx context.Context, cfg *Config, names string) ([]Sink, error) {
+
+	var sinks []Sink
+
+	for _, name := range strings.Split(names, ",") {
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		factory, ok := sinkFactories[name]
+		if !ok {
+			CloseSinks(sinks)
+			return nil, fmt.Errorf("unknown sink %q, expected one of %s", name, registeredSinks())
+		}
+
+		sink, err := factory(ctx, cfg)
+		if err != nil {
+			CloseSinks(sinks)
+			return nil, fmt.Errorf("failed to open sink %q: %w", name, err)
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	if len(sinks) == 0 {
+		return nil, fmt.Errorf("no sinks in %q", names)
+	}
+
+	return sinks, nil
+}
+
+// CloseSinks - closes each sink, returns the first error
+func CloseSinks(sinks []Sink) error {
+
+	var first error
+
+	for _, sink := range sinks {
+		if err := sink.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+func registeredSinks() string {
+
+	names := make([]string, 0, len(sinkFactories))
+	for name := range sinkFactories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,413 @@
+package hsldatabridge
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// spillPollInterval - how often Drain checks for newly spilled messages, also
+// the longest a spilled message waits in the active segment before it's sealed
+const spillPollInterval = 250 * time.Millisecond
+
+// spillSegmentBytes - approx. size of each segment file
+const spillSegmentBytes = 8 << 20
+
+// ErrSpillQueueFull - returned by Push when the queue is at its size limit
+var ErrSpillQueueFull = errors.New("spill queue full")
+
+// ErrSpillQueueClosed - returned by Push once the queue is closed
+var ErrSpillQueueClosed = errors.New("spill queue closed")
+
+// SpillQueue - A bounded, on-disk queue of staged messages. Used by MsgBroker
+// to hold messages while the staging channel is full rather than dropping them.
+//
+// Messages are appended to the active segment file; Drain seals the active segment
+// and pushes the sealed segments (oldest first) back into the staging channel,
+// deleting each once it's fully drained. Segments left over from a previous run
+// (incl. a segment Close interrupted part way through) are drained on start.
+//
+// Records are synced to disk when their segment is sealed, or on each Push if
+// Durable is set. W.o. Durable a machine crash (not a crash of the process) may lose
+// the records not yet synced
+type SpillQueue struct {
+	dir          string
+	maxBytes     int64
+	segmentBytes int64
+
+	// Durable - sync each record before Push returns, set w. at-least-once delivery
+	// where a message is acked once spilled
+	Durable bool
+
+	mu      sync.Mutex
+	sealed  []string // Segment files ready to drain, oldest first
+	active  *os.File
+	activeN int64
+	seq     uint64
+
+	closed   bool
+	draining bool
+	done     chan struct{} // Closed by Close, stops Drain
+	stopped  chan struct{} // Closed once Drain returns
+
+	size    int64  // Bytes across all segments, atomic
+	spilled uint64 // Messages pushed, atomic
+	drained uint64 // Messages returned to the staging channel, atomic
+}
+
+// NewSpillQueue - opens a spill queue in dir, holding up to maxBytes across
+// segment files of (approx.) segmentBytes each
+func NewSpillQueue(dir string, maxBytes int64, segmentBytes int64) (*SpillQueue, error) {
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	q := &SpillQueue{
+		dir:          dir,
+		maxBytes:     maxBytes,
+		segmentBytes: segmentBytes,
+		done:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+
+	// Pick up any segments left behind by a previous run
+	segments, err := filepath.Glob(filepath.Join(dir, "seg-*.log"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(segments)
+
+	for _, path := range segments {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		q.sealed = append(q.sealed, path)
+		q.size += info.Size()
+		fmt.Sscanf(filepath.Base(path), "seg-%d.log", &q.seq)
+	}
+
+	if len(segments) > 0 {
+		log.WithFields(log.Fields{
+			"Dir": dir, "Segments": len(segments), "Bytes": q.size,
+		}).Warn("Recovered Spilled Messages")
+	}
+
+	return q, nil
+}
+
+// Size - returns the bytes currently held on disk
+func (q *SpillQueue) Size() int64 {
+	return atomic.LoadInt64(&q.size)
+}
+
+// Spilled - returns the count of messages pushed to the queue
+func (q *SpillQueue) Spilled() uint64 {
+	return atomic.LoadUint64(&q.spilled)
+}
+
+// Drained - returns the count of messages returned to the staging channel
+func (q *SpillQueue) Drained() uint64 {
+	return atomic.LoadUint64(&q.drained)
+}
+
+// Push - appends a message to the active segment, returns ErrSpillQueueFull
+// if the message would take the queue past its size limit
+func (q *SpillQueue) Push(msg *StagedMessage) error {
+
+	rec := encodeSpillRecord(msg)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrSpillQueueClosed
+	}
+
+	if q.Size()+int64(len(rec)) > q.maxBytes {
+		return ErrSpillQueueFull
+	}
+
+	// Start a new segment when the active one is full
+	if q.active != nil && q.activeN+int64(len(rec)) > q.segmentBytes {
+		q.seal()
+	}
+
+	if q.active == nil {
+		q.seq++
+		f, err := os.Create(filepath.Join(q.dir, fmt.Sprintf("seg-%020d.log", q.seq)))
+		if err != nil {
+			return err
+		}
+		q.active, q.activeN = f, 0
+	}
+
+	n, err := q.active.Write(rec)
+	q.activeN += int64(n)
+	atomic.AddInt64(&q.size, int64(n))
+
+	if err != nil {
+		return err
+	}
+
+	if q.Durable {
+		if err := q.active.Sync(); err != nil {
+			return err
+		}
+	}
+
+	atomic.AddUint64(&q.spilled, 1)
+	return nil
+}
+
+// seal - closes the active segment and queues it for draining, must be
+// called w. q.mu held
+func (q *SpillQueue) seal() {
+	if err := q.active.Sync(); err != nil {
+		log.WithFields(log.Fields{"Segment": q.active.Name()}).Errorf("Failed to Sync Segment: %+v", err)
+	}
+	q.active.Close()
+	q.sealed = append(q.sealed, q.active.Name())
+	q.active, q.activeN = nil, 0
+}
+
+// next - returns the oldest sealed segment, sealing the active segment if no
+// others are waiting; returns "" if the queue is empty
+func (q *SpillQueue) next() string {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.sealed) == 0 && q.active != nil && q.activeN > 0 {
+		q.seal()
+	}
+
+	if len(q.sealed) == 0 {
+		return ""
+	}
+
+	return q.sealed[0]
+}
+
+// Drain - pushes spilled messages back into C as workers catch up, blocks
+// until Close; run on a separate goroutine
+func (q *SpillQueue) Drain(C chan<- *StagedMessage) {
+
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.draining = true
+	q.mu.Unlock()
+
+	defer close(q.stopped)
+
+	for {
+		path := q.next()
+
+		if path == "" {
+			select {
+			case <-q.done:
+				return
+			case <-time.After(spillPollInterval):
+			}
+			continue
+		}
+
+		if err := q.drainSegment(path, C); err == errSpillDrainStopped {
+			// Keep the segment, messages already drained from it are
+			// sent again on the next run
+			return
+		} else if err != nil {
+			log.WithFields(log.Fields{"Segment": path}).Errorf("Failed to Drain Segment: %+v", err)
+		}
+
+		// NOTE: A corrupt segment is dropped rather than retried forever
+		info, err := os.Stat(path)
+		if err == nil {
+			atomic.AddInt64(&q.size, -info.Size())
+		}
+
+		os.Remove(path)
+
+		q.mu.Lock()
+		q.sealed = q.sealed[1:]
+		q.mu.Unlock()
+	}
+}
+
+// errSpillDrainStopped - returned by drainSegment if Close is called part
+// way through a segment
+var errSpillDrainStopped = errors.New("spill queue drain stopped")
+
+// drainSegment - reads each record in a sealed segment into C
+func (q *SpillQueue) drainSegment(path string, C chan<- *StagedMessage) error {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Lengths read from the segment are bounded by what's left of it (and by the
+	// queue's size limit), s.t. a corrupt length can't ask for gigabytes
+	remaining := info.Size()
+
+	r := bufio.NewReader(f)
+
+	for {
+		limit := remaining
+		if limit > q.maxBytes {
+			limit = q.maxBytes
+		}
+
+		msg, err := decodeSpillRecord(r, limit)
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		remaining -= spillRecordSize(msg)
+
+		select {
+		case C <- msg:
+			atomic.AddUint64(&q.drained, 1)
+		case <-q.done:
+			return errSpillDrainStopped
+		}
+	}
+}
+
+// Close - stops Drain and closes the active segment, anything left on disk
+// is recovered by NewSpillQueue on the next run. Returns once Drain has
+// stopped sending, s.t. the staging channel can be closed safely
+func (q *SpillQueue) Close() error {
+
+	q.mu.Lock()
+
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+
+	q.closed = true
+	close(q.done)
+
+	var err error
+	if q.active != nil {
+		err = q.active.Sync()
+		if cerr := q.active.Close(); err == nil {
+			err = cerr
+		}
+		q.active, q.activeN = nil, 0
+	}
+
+	draining := q.draining
+	q.mu.Unlock()
+
+	if draining {
+		<-q.stopped
+	}
+
+	return err
+}
+
+// encodeSpillRecord - encodes a message as [received][len(topic)][topic][len(payload)][payload]
+// w. received time (unix ns) and lengths as uvarints
+func encodeSpillRecord(msg *StagedMessage) []byte {
+
+	rec := make([]byte, 0, 3*binary.MaxVarintLen64+len(msg.Topic)+len(msg.Payload))
+
+	rec = appendUvarint(rec, uint64(msg.Received.UnixNano()))
+	rec = appendUvarint(rec, uint64(len(msg.Topic)))
+	rec = append(rec, msg.Topic...)
+	rec = appendUvarint(rec, uint64(len(msg.Payload)))
+	rec = append(rec, msg.Payload...)
+
+	return rec
+}
+
+// spillRecordSize - returns the size of the message as encoded by encodeSpillRecord
+func spillRecordSize(msg *StagedMessage) int64 {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(msg.Received.UnixNano()))
+	n += binary.PutUvarint(buf[:], uint64(len(msg.Topic))) + len(msg.Topic)
+	n += binary.PutUvarint(buf[:], uint64(len(msg.Payload))) + len(msg.Payload)
+	return int64(n)
+}
+
+// decodeSpillRecord - reads a single record written by encodeSpillRecord, at most
+// limit bytes long; a record cut short (or w. a length past limit, i.e. corrupt)
+// returns io.ErrUnexpectedEOF
+func decodeSpillRecord(r *bufio.Reader, limit int64) (*StagedMessage, error) {
+
+	recv, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	topic, err := readSpillField(r, limit)
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	payload, err := readSpillField(r, limit-int64(len(topic)))
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return &StagedMessage{
+		Topic:    string(topic),
+		Payload:  payload,
+		Received: time.Unix(0, int64(recv)),
+	}, nil
+}
+
+// readSpillField - reads a length-prefixed field of up to limit bytes
+func readSpillField(r *bufio.Reader, limit int64) ([]byte, error) {
+
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit < 0 || n > uint64(limit) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return b, nil
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
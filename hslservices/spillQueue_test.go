@@ -0,0 +1,249 @@
+package hsldatabridge
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// spillTestMessages - messages w. an empty, a short, and a large (multi-byte length) payload
+func spillTestMessages() []*StagedMessage {
+	return []*StagedMessage{
+		{
+			Topic:    "/hfp/v2/journey/ongoing/vp/bus/0018/00423/2159/2/Matinkylä (M)/09:32/2442201/3/60;24/16/58/67",
+			Payload:  []byte(`{"VP":{"veh":423,"tsi":1620979200,"lat":60.16,"long":24.74}}`),
+			Received: time.Unix(0, 1620979200123456789),
+		},
+		{
+			Topic:    "/hfp/v2/deadrun/ongoing/vp/bus/0022/00845//////0/60;25/20/21/45",
+			Payload:  []byte{},
+			Received: time.Unix(0, 1),
+		},
+		{
+			Topic:    "",
+			Payload:  bytes.Repeat([]byte("x"), 70000),
+			Received: time.Unix(1620979200, 0),
+		},
+	}
+}
+
+func TestSpillRecordRoundTrip(t *testing.T) {
+
+	var buf bytes.Buffer
+	for _, msg := range spillTestMessages() {
+		buf.Write(encodeSpillRecord(msg))
+	}
+
+	r := bufio.NewReader(&buf)
+
+	for _, want := range spillTestMessages() {
+		got, err := decodeSpillRecord(r, 1<<20)
+		if err != nil {
+			t.Fatalf("decodeSpillRecord: %+v", err)
+		}
+
+		if got.Topic != want.Topic || !bytes.Equal(got.Payload, want.Payload) || !got.Received.Equal(want.Received) {
+			t.Errorf("decodeSpillRecord: got (%q, %d bytes, %s), want (%q, %d bytes, %s)",
+				got.Topic, len(got.Payload), got.Received, want.Topic, len(want.Payload), want.Received)
+		}
+	}
+
+	if _, err := decodeSpillRecord(r, 1<<20); err != io.EOF {
+		t.Errorf("decodeSpillRecord after last record: got %v, want io.EOF", err)
+	}
+}
+
+func TestSpillRecordTruncated(t *testing.T) {
+
+	rec := encodeSpillRecord(spillTestMessages()[0])
+
+	// Cut inside the received time, the topic, and the payload
+	for _, n := range []int{1, 12, len(rec) - 1} {
+		_, err := decodeSpillRecord(bufio.NewReader(bytes.NewReader(rec[:n])), int64(n))
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("decodeSpillRecord of %d/%d bytes: got %v, want io.ErrUnexpectedEOF", n, len(rec), err)
+		}
+	}
+}
+
+func TestSpillRecordCorruptLength(t *testing.T) {
+
+	msg := spillTestMessages()[0]
+	if got := spillRecordSize(msg); got != int64(len(encodeSpillRecord(msg))) {
+		t.Fatalf("spillRecordSize: got %d, want %d", got, len(encodeSpillRecord(msg)))
+	}
+
+	// A topic claiming to be ~1TB long, read before anything is allocated
+	rec := appendUvarint(nil, uint64(msg.Received.UnixNano()))
+	rec = appendUvarint(rec, 1<<40)
+	rec = append(rec, msg.Topic...)
+
+	_, err := decodeSpillRecord(bufio.NewReader(bytes.NewReader(rec)), int64(len(rec)))
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("decodeSpillRecord w. a corrupt length: got %v, want io.ErrUnexpectedEOF", err)
+	}
+
+	// Within the record, but past the limit
+	rec = encodeSpillRecord(msg)
+	if _, err := decodeSpillRecord(bufio.NewReader(bytes.NewReader(rec)), int64(len(msg.Topic))-1); err != io.ErrUnexpectedEOF {
+		t.Errorf("decodeSpillRecord past limit: got %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestSpillQueueFull(t *testing.T) {
+
+	msg := spillTestMessages()[0]
+	rec := encodeSpillRecord(msg)
+
+	q, err := NewSpillQueue(t.TempDir(), int64(2*len(rec)), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := q.Push(msg); err != nil {
+			t.Fatalf("Push %d: %+v", i, err)
+		}
+	}
+
+	if err := q.Push(msg); err != ErrSpillQueueFull {
+		t.Errorf("Push past maxBytes: got %v, want ErrSpillQueueFull", err)
+	}
+
+	if q.Spilled() != 2 || q.Size() != int64(2*len(rec)) {
+		t.Errorf("got Spilled %d, Size %d; want 2, %d", q.Spilled(), q.Size(), 2*len(rec))
+	}
+}
+
+// drainAll - drains q into a channel until want messages arrive, or a timeout
+func drainAll(t *testing.T, q *SpillQueue, want int) []*StagedMessage {
+
+	C := make(chan *StagedMessage, want+1)
+	go q.Drain(C)
+
+	var msgs []*StagedMessage
+	timeout := time.After(5 * time.Second)
+
+	for len(msgs) < want {
+		select {
+		case msg := <-C:
+			msgs = append(msgs, msg)
+		case <-timeout:
+			t.Fatalf("drained %d messages, want %d", len(msgs), want)
+		}
+	}
+
+	return msgs
+}
+
+func TestSpillQueueRecoversSegments(t *testing.T) {
+
+	dir := t.TempDir()
+
+	// Small segments s.t. messages span several files
+	q, err := NewSpillQueue(dir, 1<<20, 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want []string
+	for i := 0; i < 10; i++ {
+		msg := spillTestMessages()[0]
+		msg.Topic = msg.Topic + string(rune('a'+i))
+		want = append(want, msg.Topic)
+
+		if err := q.Push(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopen, i.e. after a restart
+	q, err = NewSpillQueue(dir, 1<<20, 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	var got []string
+	for _, msg := range drainAll(t, q, len(want)) {
+		got = append(got, msg.Topic)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recovered topics out of order\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestSpillQueueTruncatedSegment(t *testing.T) {
+
+	dir := t.TempDir()
+
+	q, err := NewSpillQueue(dir, 1<<20, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := spillTestMessages()
+	for _, msg := range msgs {
+		if err := q.Push(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cut the last record short, e.g. a crash part way through a write
+	segments, _ := filepath.Glob(filepath.Join(dir, "seg-*.log"))
+	if len(segments) != 1 {
+		t.Fatalf("got %d segments, want 1", len(segments))
+	}
+
+	info, err := os.Stat(segments[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Truncate(segments[0], info.Size()-100); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err = NewSpillQueue(dir, 1<<20, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	// Every record before the cut is recovered...
+	got := drainAll(t, q, len(msgs)-1)
+	for i, msg := range got {
+		if msg.Topic != msgs[i].Topic {
+			t.Errorf("message %d: got topic %q, want %q", i, msg.Topic, msgs[i].Topic)
+		}
+	}
+
+	// ...and the segment is dropped rather than drained again
+	deadline := time.Now().Add(5 * time.Second)
+	for q.Size() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if q.Size() != 0 {
+		t.Errorf("got Size %d after draining, want 0", q.Size())
+	}
+
+	if segments, _ := filepath.Glob(filepath.Join(dir, "seg-*.log")); len(segments) != 0 {
+		t.Errorf("got segments %q after draining, want none", segments)
+	}
+}
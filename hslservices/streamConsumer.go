@@ -0,0 +1,459 @@
+package hsldatabridge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+	log "github.com/sirupsen/logrus"
+)
+
+// StreamHandler - processes a batch of stream entries, the batch is acked if the
+// handler returns nil, otherwise it stays pending and is retried (possibly by
+// another consumer) once idle for longer than MinIdle
+type StreamHandler func(ctx context.Context, msgs []redis.XMessage) error
+
+// StreamConsumer - Reads a Redis stream through a consumer group. Downstream
+// processors of the `events` stream (e.g. cmd/writebehind) are built on this.
+//
+// New entries are read w. XREADGROUP. Entries left pending by a failed batch, or
+// by a consumer that crashed, are reclaimed w. XAUTOCLAIM once idle for MinIdle.
+// Entries delivered more than MaxDeliveries times are moved to the DeadLetter
+// stream rather than retried forever.
+//
+// W. TrimInterval, the stream is trimmed (XTRIM MINID) to the oldest entry any
+// group still needs, s.t. other groups reading the same stream lose nothing.
+type StreamConsumer struct {
+	Stream   string
+	Group    string
+	Consumer string
+
+	BatchSize     int64         // Max entries per batch
+	Block         time.Duration // Max wait for new entries
+	MinIdle       time.Duration // Pending entries idle for longer are reclaimed
+	MaxDeliveries int64         // Entries delivered more often are dead-lettered, 0 to never dead-letter
+	DeadLetter    string        // Stream for dead-lettered entries, e.g. `events:dead`
+	DeadLetterLen int64         // Approx. max length of the dead-letter stream
+	RetryAfter    time.Duration // Wait after a failed batch or Redis error
+	TrimInterval  time.Duration // Trim entries every group has read && acked this often, 0 to never trim
+
+	client      *redis.Client
+	claimCursor string
+	lastClaim   time.Time
+	lastTrim    time.Time
+}
+
+// NewStreamConsumer - creates a consumer w. defaults matching the old Gears write-behind
+// (batches of up to 10,000, every 5s); dead-letters to `<stream>:dead` after 5 deliveries
+func NewStreamConsumer(client *redis.Client, stream string, group string, consumer string) *StreamConsumer {
+	return &StreamConsumer{
+		Stream:        stream,
+		Group:         group,
+		Consumer:      consumer,
+		BatchSize:     10000,
+		Block:         5 * time.Second,
+		MinIdle:       60 * time.Second,
+		MaxDeliveries: 5,
+		DeadLetter:    fmt.Sprintf("%s:dead", stream),
+		DeadLetterLen: 100000,
+		RetryAfter:    5 * time.Second,
+		client:        client,
+		claimCursor:   "0-0",
+	}
+}
+
+// CreateGroup - creates the consumer group (and stream) if they don't exist, new
+// groups start from the beginning of the stream s.t. nothing already written
+// is skipped
+func (sc *StreamConsumer) CreateGroup(ctx context.Context) error {
+
+	err := sc.client.XGroupCreateMkStream(ctx, sc.Stream, sc.Group, "0").Err()
+
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	return nil
+}
+
+// Run - reads and handles batches until ctx is cancelled
+func (sc *StreamConsumer) Run(ctx context.Context, handler StreamHandler) error {
+
+	if err := sc.CreateGroup(ctx); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+
+		var (
+			msgs []redis.XMessage
+			err  error
+		)
+
+		// Check for stale pending entries about as often as they can become
+		// stale, otherwise read new entries
+		if time.Since(sc.lastClaim) >= sc.MinIdle {
+			msgs, err = sc.reclaim(ctx)
+		} else {
+			msgs, err = sc.read(ctx)
+		}
+
+		if err != nil {
+			if ctx.Err() == nil {
+				log.WithFields(log.Fields{"Stream": sc.Stream, "Group": sc.Group}).Errorf("Failed to Read Stream: %+v", err)
+				sleepCtx(ctx, sc.RetryAfter)
+			}
+			continue
+		}
+
+		if len(msgs) == 0 {
+			continue
+		}
+
+		if err := handler(ctx, msgs); err != nil {
+			// Leave the batch pending, reclaimed once idle for MinIdle
+			log.WithFields(
+				log.Fields{"Stream": sc.Stream, "Group": sc.Group, "Entries": len(msgs)},
+			).Errorf("Failed to Handle Batch: %+v", err)
+			sleepCtx(ctx, sc.RetryAfter)
+			continue
+		}
+
+		if err := sc.Ack(ctx, msgs); err != nil {
+			log.WithFields(
+				log.Fields{"Stream": sc.Stream, "Group": sc.Group, "Entries": len(msgs)},
+			).Errorf("Failed to Ack Batch: %+v", err)
+		}
+
+		if sc.TrimInterval > 0 && time.Since(sc.lastTrim) >= sc.TrimInterval {
+			if err := sc.Trim(ctx); err != nil {
+				log.WithFields(log.Fields{"Stream": sc.Stream}).Errorf("Failed to Trim Stream: %+v", err)
+			}
+			sc.lastTrim = time.Now()
+		}
+	}
+
+	return ctx.Err()
+}
+
+// Ack - XACK the entries
+func (sc *StreamConsumer) Ack(ctx context.Context, msgs []redis.XMessage) error {
+
+	ids := make([]string, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.ID
+	}
+
+	return sc.client.XAck(ctx, sc.Stream, sc.Group, ids...).Err()
+}
+
+// Trim - XTRIM MINID the stream to the oldest entry still needed by any of its
+// groups, i.e. the group's oldest pending entry, or (if none are pending) the
+// last entry delivered to the group. No-op if the stream has no groups
+func (sc *StreamConsumer) Trim(ctx context.Context) error {
+
+	// NOTE: go-redis v8.8 expects the Redis 6.2 XINFO GROUPS reply, read it raw
+	reply, err := sc.client.Do(ctx, "XINFO", "GROUPS", sc.Stream).Result()
+	if err != nil {
+		return err
+	}
+
+	groups, err := parseInfoGroups(reply)
+	if err != nil || len(groups) == 0 {
+		return err
+	}
+
+	minID, err := trimPoint(groups, func(group string) (string, error) {
+		pending, err := sc.client.XPending(ctx, sc.Stream, group).Result()
+		if err != nil {
+			return "", err
+		}
+		return pending.Lower, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// Approx. trim, Redis only removes whole nodes s.t. a few older entries may remain
+	return sc.client.Do(ctx, "XTRIM", sc.Stream, "MINID", "~", minID).Err()
+}
+
+// read - XREADGROUP new entries, blocks for up to Block
+func (sc *StreamConsumer) read(ctx context.Context) ([]redis.XMessage, error) {
+
+	streams, err := sc.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    sc.Group,
+		Consumer: sc.Consumer,
+		Streams:  []string{sc.Stream, ">"},
+		Count:    sc.BatchSize,
+		Block:    sc.Block,
+	}).Result()
+
+	// No new entries within Block
+	if err == redis.Nil {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return streams[0].Messages, nil
+}
+
+// reclaim - XAUTOCLAIM entries pending (w. any consumer) for longer than MinIdle,
+// dead-letters those past MaxDeliveries and returns the rest for retry
+func (sc *StreamConsumer) reclaim(ctx context.Context) ([]redis.XMessage, error) {
+
+	// NOTE: go-redis v8.8 has no XAUTOCLAIM, reply is [cursor, [[id, [k, v, ...]], ...]]
+	reply, err := sc.client.Do(
+		ctx, "XAUTOCLAIM", sc.Stream, sc.Group, sc.Consumer,
+		sc.MinIdle.Milliseconds(), sc.claimCursor, "COUNT", sc.BatchSize,
+	).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, msgs, err := parseAutoClaim(reply)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cursor returns to 0-0 once the whole PEL has been scanned, wait
+	// MinIdle before the next scan
+	sc.claimCursor = cursor
+	if cursor == "0-0" {
+		sc.lastClaim = time.Now()
+	}
+
+	if len(msgs) == 0 || sc.MaxDeliveries <= 0 {
+		return msgs, nil
+	}
+
+	// Delivery counts for the claimed range, all are now pending w. this consumer
+	pending, err := sc.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream:   sc.Stream,
+		Group:    sc.Group,
+		Start:    msgs[0].ID,
+		End:      msgs[len(msgs)-1].ID,
+		Count:    int64(len(msgs)),
+		Consumer: sc.Consumer,
+	}).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make(map[string]int64, len(pending))
+	for _, p := range pending {
+		deliveries[p.ID] = p.RetryCount
+	}
+
+	var retry, dead []redis.XMessage
+	for _, msg := range msgs {
+		if deliveries[msg.ID] > sc.MaxDeliveries {
+			dead = append(dead, msg)
+		} else {
+			retry = append(retry, msg)
+		}
+	}
+
+	if len(dead) > 0 {
+		if err := sc.deadLetter(ctx, dead, deliveries); err != nil {
+			return nil, err
+		}
+	}
+
+	return retry, nil
+}
+
+// deadLetter - copies entries to the DeadLetter stream and acks them on the
+// source stream in a single transaction
+func (sc *StreamConsumer) deadLetter(ctx context.Context, msgs []redis.XMessage, deliveries map[string]int64) error {
+
+	pipe := sc.client.TxPipeline()
+
+	for _, msg := range msgs {
+
+		values := []interface{}{
+			"src_stream", sc.Stream,
+			"src_id", msg.ID,
+			"src_group", sc.Group,
+			"deliveries", deliveries[msg.ID],
+		}
+
+		for k, v := range msg.Values {
+			values = append(values, k, v)
+		}
+
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream:       sc.DeadLetter,
+			MaxLenApprox: sc.DeadLetterLen,
+			Values:       values,
+		})
+	}
+
+	ids := make([]string, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.ID
+	}
+
+	pipe.XAck(ctx, sc.Stream, sc.Group, ids...)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"Stream": sc.Stream, "Group": sc.Group, "DeadLetter": sc.DeadLetter, "Entries": len(msgs),
+	}).Warn("Dead-Lettered Entries")
+
+	return nil
+}
+
+// trimPoint - returns the oldest entry still needed by any of groups, i.e. the
+// oldest entry pending w. the group (see oldestPending), or for a group w.o.
+// pending entries, the last entry delivered to it
+func trimPoint(groups []redis.XInfoGroup, oldestPending func(group string) (string, error)) (string, error) {
+
+	var minID string
+
+	for _, g := range groups {
+
+		needed := g.LastDeliveredID
+		if g.Pending > 0 {
+			lower, err := oldestPending(g.Name)
+			if err != nil {
+				return "", err
+			}
+			needed = lower
+		}
+
+		if minID == "" || compareStreamIDs(needed, minID) < 0 {
+			minID = needed
+		}
+	}
+
+	return minID, nil
+}
+
+// parseAutoClaim - parses the XAUTOCLAIM reply; entries deleted from the stream
+// while pending are returned as nil by Redis 6.2 and skipped
+func parseAutoClaim(reply interface{}) (string, []redis.XMessage, error) {
+
+	arr, ok := reply.([]interface{})
+	if !ok || len(arr) < 2 {
+		return "", nil, fmt.Errorf("unexpected XAUTOCLAIM reply %v", reply)
+	}
+
+	cursor, _ := arr[0].(string)
+	entries, _ := arr[1].([]interface{})
+
+	msgs := make([]redis.XMessage, 0, len(entries))
+
+	for _, entry := range entries {
+
+		pair, ok := entry.([]interface{})
+		if !ok || len(pair) < 2 {
+			continue
+		}
+
+		id, _ := pair[0].(string)
+		fields, _ := pair[1].([]interface{})
+
+		values := make(map[string]interface{}, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			if k, ok := fields[i].(string); ok {
+				values[k] = fields[i+1]
+			}
+		}
+
+		msgs = append(msgs, redis.XMessage{ID: id, Values: values})
+	}
+
+	return cursor, msgs, nil
+}
+
+// parseInfoGroups - parses the XINFO GROUPS reply, fields added after Redis 6.2
+// (e.g. `entries-read`, `lag`) are ignored
+func parseInfoGroups(reply interface{}) ([]redis.XInfoGroup, error) {
+
+	arr, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected XINFO GROUPS reply %v", reply)
+	}
+
+	groups := make([]redis.XInfoGroup, 0, len(arr))
+
+	for _, entry := range arr {
+
+		fields, ok := entry.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected XINFO GROUPS entry %v", entry)
+		}
+
+		var g redis.XInfoGroup
+		for i := 0; i+1 < len(fields); i += 2 {
+			k, _ := fields[i].(string)
+			switch v := fields[i+1].(type) {
+			case string:
+				switch k {
+				case "name":
+					g.Name = v
+				case "last-delivered-id":
+					g.LastDeliveredID = v
+				}
+			case int64:
+				switch k {
+				case "consumers":
+					g.Consumers = v
+				case "pending":
+					g.Pending = v
+				}
+			}
+		}
+
+		groups = append(groups, g)
+	}
+
+	return groups, nil
+}
+
+// compareStreamIDs - compares two stream IDs (`<ms>-<seq>`), returns -1, 0, or 1
+func compareStreamIDs(a string, b string) int {
+
+	parse := func(id string) (uint64, uint64) {
+		parts := strings.SplitN(id, "-", 2)
+		ms, _ := strconv.ParseUint(parts[0], 10, 64)
+		var seq uint64
+		if len(parts) == 2 {
+			seq, _ = strconv.ParseUint(parts[1], 10, 64)
+		}
+		return ms, seq
+	}
+
+	ams, aseq := parse(a)
+	bms, bseq := parse(b)
+
+	switch {
+	case ams < bms || (ams == bms && aseq < bseq):
+		return -1
+	case ams > bms || (ams == bms && aseq > bseq):
+		return 1
+	}
+
+	return 0
+}
+
+// sleepCtx - sleeps for d or until ctx is cancelled
+func sleepCtx(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -0,0 +1,200 @@
+package hsldatabridge
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	redis "github.com/go-redis/redis/v8"
+)
+
+func TestCompareStreamIDs(t *testing.T) {
+
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"1620979200000-0", "1620979200000-0", 0},
+		{"1620979200000-0", "1620979200000-1", -1},
+		{"1620979200000-10", "1620979200000-9", 1}, // Compared as numbers, not strings
+		{"1620979200000-5", "1620979200001-0", -1},
+		{"1620979200001-0", "1620979200000-99", 1},
+		{"999-0", "1000-0", -1},
+		{"1620979200000", "1620979200000-0", 0}, // Sequence defaults to 0
+		{"1620979200000", "1620979200000-1", -1},
+		{"0-0", "0-1", -1},
+	} {
+		if got := compareStreamIDs(tc.a, tc.b); got != tc.want {
+			t.Errorf("compareStreamIDs(%s, %s): got %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+		if got := compareStreamIDs(tc.b, tc.a); got != -tc.want {
+			t.Errorf("compareStreamIDs(%s, %s): got %d, want %d", tc.b, tc.a, got, -tc.want)
+		}
+	}
+}
+
+func TestParseInfoGroups(t *testing.T) {
+
+	for _, tc := range []struct {
+		name  string
+		reply interface{}
+		want  []redis.XInfoGroup
+	}{
+		{
+			name:  "no groups",
+			reply: []interface{}{},
+			want:  []redis.XInfoGroup{},
+		},
+		{
+			name: "redis 6.2",
+			reply: []interface{}{
+				[]interface{}{"name", "writebehind", "consumers", int64(2), "pending", int64(3), "last-delivered-id", "1620979200000-5"},
+				[]interface{}{"name", "archive", "consumers", int64(0), "pending", int64(0), "last-delivered-id", "0-0"},
+			},
+			want: []redis.XInfoGroup{
+				{Name: "writebehind", Consumers: 2, Pending: 3, LastDeliveredID: "1620979200000-5"},
+				{Name: "archive", Consumers: 0, Pending: 0, LastDeliveredID: "0-0"},
+			},
+		},
+		{
+			name: "redis 7 w. entries-read && lag",
+			reply: []interface{}{
+				[]interface{}{
+					"name", "writebehind", "consumers", int64(1), "pending", int64(0),
+					"last-delivered-id", "1620979200000-0", "entries-read", int64(42), "lag", nil,
+				},
+			},
+			want: []redis.XInfoGroup{
+				{Name: "writebehind", Consumers: 1, Pending: 0, LastDeliveredID: "1620979200000-0"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseInfoGroups(tc.reply)
+			if err != nil {
+				t.Fatalf("parseInfoGroups: %+v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseInfoGroups\n got: %+v\nwant: %+v", got, tc.want)
+			}
+		})
+	}
+
+	for _, reply := range []interface{}{nil, "OK", []interface{}{"name", "writebehind"}} {
+		if _, err := parseInfoGroups(reply); err == nil {
+			t.Errorf("parseInfoGroups(%v): got nil error", reply)
+		}
+	}
+}
+
+func TestTrimPoint(t *testing.T) {
+
+	// Oldest pending entry per group
+	pending := map[string]string{
+		"writebehind": "1620979200000-2",
+		"archive":     "1620979100000-0",
+	}
+
+	oldestPending := func(group string) (string, error) {
+		id, ok := pending[group]
+		if !ok {
+			return "", errors.New("no pending entries")
+		}
+		return id, nil
+	}
+
+	for _, tc := range []struct {
+		name   string
+		groups []redis.XInfoGroup
+		want   string
+	}{
+		{
+			name:   "w.o. pending entries, up to the last delivered",
+			groups: []redis.XInfoGroup{{Name: "writebehind", LastDeliveredID: "1620979200000-7"}},
+			want:   "1620979200000-7",
+		},
+		{
+			name:   "w. pending entries, up to the oldest pending",
+			groups: []redis.XInfoGroup{{Name: "writebehind", Pending: 3, LastDeliveredID: "1620979200000-7"}},
+			want:   "1620979200000-2",
+		},
+		{
+			name: "slowest group",
+			groups: []redis.XInfoGroup{
+				{Name: "writebehind", Pending: 3, LastDeliveredID: "1620979200000-7"},
+				{Name: "tiles", LastDeliveredID: "1620979200000-10"},
+				{Name: "archive", Pending: 1, LastDeliveredID: "1620979200000-0"},
+			},
+			want: "1620979100000-0",
+		},
+		{
+			name: "slowest group by sequence",
+			groups: []redis.XInfoGroup{
+				{Name: "tiles", LastDeliveredID: "1620979200000-10"},
+				{Name: "writebehind", Pending: 1, LastDeliveredID: "1620979200000-12"},
+			},
+			want: "1620979200000-2",
+		},
+		{
+			name: "group that hasn't read anything",
+			groups: []redis.XInfoGroup{
+				{Name: "writebehind", LastDeliveredID: "1620979200000-7"},
+				{Name: "new", LastDeliveredID: "0-0"},
+			},
+			want: "0-0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := trimPoint(tc.groups, oldestPending)
+			if err != nil {
+				t.Fatalf("trimPoint: %+v", err)
+			}
+			if got != tc.want {
+				t.Errorf("trimPoint: got %s, want %s", got, tc.want)
+			}
+		})
+	}
+
+	// Can't tell what a group still needs, don't trim
+	if _, err := trimPoint([]redis.XInfoGroup{{Name: "unknown", Pending: 1}}, oldestPending); err == nil {
+		t.Errorf("trimPoint w. a failed XPENDING: got nil error")
+	}
+}
+
+func TestParseAutoClaim(t *testing.T) {
+
+	reply := []interface{}{
+		"1620979200000-9",
+		[]interface{}{
+			[]interface{}{"1620979200000-3", []interface{}{"topic", "/hfp/v2/journey/ongoing/vp/bus/0018/00423", "body", `{"VP":{}}`}},
+			nil, // Deleted from the stream while pending
+			[]interface{}{"1620979200000-10", []interface{}{}},
+		},
+	}
+
+	cursor, msgs, err := parseAutoClaim(reply)
+	if err != nil {
+		t.Fatalf("parseAutoClaim: %+v", err)
+	}
+
+	want := []redis.XMessage{
+		{ID: "1620979200000-3", Values: map[string]interface{}{"topic": "/hfp/v2/journey/ongoing/vp/bus/0018/00423", "body": `{"VP":{}}`}},
+		{ID: "1620979200000-10", Values: map[string]interface{}{}},
+	}
+
+	if cursor != "1620979200000-9" || !reflect.DeepEqual(msgs, want) {
+		t.Errorf("parseAutoClaim\n got: %s %+v\nwant: %s %+v", cursor, msgs, "1620979200000-9", want)
+	}
+
+	// Redis 7 adds the IDs of deleted entries as a third element
+	cursor, msgs, err = parseAutoClaim([]interface{}{"0-0", []interface{}{}, []interface{}{"1620979200000-4"}})
+	if err != nil || cursor != "0-0" || len(msgs) != 0 {
+		t.Errorf("parseAutoClaim w. deleted IDs: got %s %+v %v", cursor, msgs, err)
+	}
+
+	for _, reply := range []interface{}{nil, "0-0", []interface{}{"0-0"}} {
+		if _, _, err := parseAutoClaim(reply); err == nil {
+			t.Errorf("parseAutoClaim(%v): got nil error", reply)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package hsldatabridge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+	"github.com/mmcloughlin/geohash"
+	log "github.com/sirupsen/logrus"
+)
+
+// SeriesDef - A timeseries created for each journey, `positions:<journey>:<name>`,
+// fed by one field of each position (VP) and compacted by each of its rules
+type SeriesDef struct {
+	Name      string           `yaml:"name"`
+	Field     string           `yaml:"field"` // See seriesFields, e.g. spd, gh, dl, occu, acc, hdg
+	Retention time.Duration    `yaml:"retention"`
+	ChunkSize int              `yaml:"chunk_size,omitempty"` // Bytes per chunk, 0 for the RedisTimeSeries default
+	Rules     []CompactionRule `yaml:"rules"`
+	Rollup    bool             `yaml:"rollup,omitempty"` // Also sum the field by route, mode && operator, see TimeSeriesConfig
+}
+
+// CompactionRule - A compaction of a series into `positions:<journey>:<name>:<suffix>`,
+// w. one sample per bucket
+type CompactionRule struct {
+	Suffix      string        `yaml:"suffix,omitempty"` // Defaults to <aggregation>_<bucket ms>, e.g. avg_60000
+	Aggregation string        `yaml:"aggregation"`      // e.g. AVG, MAX, MIN, LAST
+	Bucket      time.Duration `yaml:"bucket"`
+	Retention   time.Duration `yaml:"retention"`
+}
+
+// seriesFields - the position fields that may feed a series, by JSON name
+var seriesFields = map[string]func(e *Event) (interface{}, bool){
+	"spd":  func(e *Event) (interface{}, bool) { return e.Spd, true },
+	"acc":  func(e *Event) (interface{}, bool) { return e.Acc, true },
+	"dl":   func(e *Event) (interface{}, bool) { return e.DeltaToSchedule, true },
+	"hdg":  func(e *Event) (interface{}, bool) { return e.Heading, true },
+	"occu": func(e *Event) (interface{}, bool) { return e.Occupancy, true },
+	"odo":  func(e *Event) (interface{}, bool) { return e.Odometer.Value, e.Odometer.Valid },
+	"gh": func(e *Event) (interface{}, bool) {
+		return geohash.EncodeIntWithPrecision(e.Lat, e.Lng, 64), true
+	},
+}
+
+// aggregations - aggregation types supported by TS.CREATERULE
+var aggregations = map[string]bool{
+	"AVG": true, "SUM": true, "MIN": true, "MAX": true, "RANGE": true, "COUNT": true,
+	"FIRST": true, "LAST": true, "STD.P": true, "STD.S": true, "VAR.P": true, "VAR.S": true,
+}
+
+// DefaultSeries - speed && geohash, compacted to the last sample every 15s for the
+// trip history layer (see `/histlocations/`), and delay, averaged each minute and
+// w. the max over 5 minutes; speed && delay are also rolled up
+func DefaultSeries() []SeriesDef {
+
+	history := []CompactionRule{
+		{Suffix: "agg", Aggregation: "LAST", Bucket: 15 * time.Second, Retention: 2 * time.Hour},
+	}
+
+	return []SeriesDef{
+		{Name: "speed", Field: "spd", Retention: time.Minute, ChunkSize: 16, Rules: history, Rollup: true},
+		{Name: "gh", Field: "gh", Retention: time.Minute, Rules: history},
+		{Name: "delay", Field: "dl", Retention: 5 * time.Minute, Rules: []CompactionRule{
+			{Aggregation: "AVG", Bucket: time.Minute, Retention: 2 * time.Hour},
+			{Aggregation: "MAX", Bucket: 5 * time.Minute, Retention: 2 * time.Hour},
+		}, Rollup: true},
+	}
+}
+
+// ValidateSeries - checks series names are unique and each field, and rule, is supported
+func ValidateSeries(defs []SeriesDef) error {
+
+	names := make(map[string]bool, len(defs))
+
+	for _, s := range defs {
+
+		switch {
+		case s.Name == "" || strings.Contains(s.Name, ":"):
+			return fmt.Errorf("invalid series name %q", s.Name)
+		case names[s.Name]:
+			return fmt.Errorf("duplicate series %q", s.Name)
+		case seriesFields[s.Field] == nil:
+			return fmt.Errorf("series %s: unknown field %q", s.Name, s.Field)
+		case s.Retention < 0 || s.ChunkSize < 0:
+			return fmt.Errorf("series %s: retention and chunk_size can't be negative", s.Name)
+		case s.Rollup && s.Field == "gh":
+			return fmt.Errorf("series %s: can't roll up a geohash", s.Name)
+		}
+
+		names[s.Name] = true
+
+		suffixes := make(map[string]bool, len(s.Rules))
+		for _, r := range s.Rules {
+			switch {
+			case !aggregations[strings.ToUpper(r.Aggregation)]:
+				return fmt.Errorf("series %s: unknown aggregation %q", s.Name, r.Aggregation)
+			case r.Bucket < time.Millisecond:
+				return fmt.Errorf("series %s: bucket must be at least 1ms, got %s", s.Name, r.Bucket)
+			case suffixes[r.suffix()]:
+				return fmt.Errorf("series %s: duplicate rule %s", s.Name, r.suffix())
+			}
+			suffixes[r.suffix()] = true
+		}
+	}
+
+	return nil
+}
+
+func (r *CompactionRule) suffix() string {
+	if r.Suffix != "" {
+		return r.Suffix
+	}
+	return fmt.Sprintf("%s_%d", strings.ToLower(r.Aggregation), r.Bucket.Milliseconds())
+}
+
+// Key - returns the key of the journey's series
+func (s *SeriesDef) Key(journeyID string) string {
+	return fmt.Sprintf("positions:%s:%s", journeyID, s.Name)
+}
+
+// RuleKey - returns the key of the journey's series compacted by r
+func (s *SeriesDef) RuleKey(journeyID string, r *CompactionRule) string {
+	return fmt.Sprintf("%s:%s", s.Key(journeyID), r.suffix())
+}
+
+// journeySeriesKeys - the timeseries created for each journey, incl. compactions
+func journeySeriesKeys(defs []SeriesDef, journeyID string) []string {
+
+	var keys []string
+
+	for i := range defs {
+		keys = append(keys, defs[i].Key(journeyID))
+		for j := range defs[i].Rules {
+			keys = append(keys, defs[i].RuleKey(journeyID, &defs[i].Rules[j]))
+		}
+	}
+
+	return keys
+}
+
+// createJourneySeries - creates each series for the journey and its compactions. Series
+// are labelled w. the journey, mode, and series name; compactions also w. the aggregation
+// and bucket (ms) s.t. TS.MRANGE can select them, e.g. `agg=LAST bucket=15000`
+func createJourneySeries(ctx context.Context, client *redis.Client, defs []SeriesDef, journeyID string, mode string) {
+
+	// Create Parent && Child Series
+	pipe := client.TxPipeline()
+
+	for i := range defs {
+		s := &defs[i]
+
+		args := []interface{}{"TS.CREATE", s.Key(journeyID), "RETENTION", s.Retention.Milliseconds()}
+		if s.ChunkSize > 0 {
+			args = append(args, "CHUNK_SIZE", s.ChunkSize)
+		}
+		pipe.Do(ctx, append(args, "LABELS", "journey", journeyID, "mode", mode, "series", s.Name)...)
+
+		for j := range s.Rules {
+			r := &s.Rules[j]
+			pipe.Do(
+				ctx, "TS.CREATE", s.RuleKey(journeyID, r),
+				"RETENTION", r.Retention.Milliseconds(),
+				"LABELS", s.Name, 1, "journey", journeyID, "mode", mode, "series", s.Name,
+				"agg", strings.ToUpper(r.Aggregation), "bucket", r.Bucket.Milliseconds(),
+			)
+		}
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.WithFields(
+			log.Fields{"JourneyID": journeyID},
+		).Warn("Create TimeSeries Root Series Failed: ", err)
+	}
+
+	// Using a second pipe, create the rules, split into 2 stages to ensure parent && child
+	// series exist first....
+	for i := range defs {
+		s := &defs[i]
+		for j := range s.Rules {
+			r := &s.Rules[j]
+			pipe.Do(
+				ctx, "TS.CREATERULE", s.Key(journeyID), s.RuleKey(journeyID, r),
+				"AGGREGATION", strings.ToUpper(r.Aggregation), r.Bucket.Milliseconds(),
+			)
+		}
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.WithFields(
+			log.Fields{"JourneyID": journeyID},
+		).Warn("Create TimeSeries Rules Failed: ", err)
+	}
+}
+
+// addJourneySamples - TS.ADDs the position to each series created by createJourneySeries
+// at ts, the vehicle's timestamp (unix ms); fields w.o. a value (e.g. `odo` sent as
+// null) are skipped
+func addJourneySamples(ctx context.Context, pipe redis.Pipeliner, defs []SeriesDef, e *Event, journeyID string, ts int64) {
+
+	for i := range defs {
+		s := &defs[i]
+
+		value, ok := seriesFields[s.Field](e)
+		if !ok {
+			continue
+		}
+
+		// NOTE: RETENTION && CHUNK_SIZE only apply if the series was never created,
+		// e.g. registering the journey failed
+		args := []interface{}{"TS.ADD", s.Key(journeyID), ts, value, "RETENTION", s.Retention.Milliseconds()}
+		if s.ChunkSize > 0 {
+			args = append(args, "CHUNK_SIZE", s.ChunkSize)
+		}
+
+		pipe.Do(ctx, append(args, "ON_DUPLICATE", "LAST")...)
+	}
+}
@@ -0,0 +1,176 @@
+package hsldatabridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pquerna/ffjson/ffjson"
+)
+
+// errNothingCoerced - the body failed to decode for some reason other than a
+// known type variant
+var errNothingCoerced = errors.New("no fields to coerce")
+
+// fieldKind - the JSON kind the decoder expects for a field
+type fieldKind int
+
+const (
+	kindInt fieldKind = iota
+	kindFloat
+	kindString
+)
+
+// fieldKinds - the expected kind of each field of the event bodies, by JSON
+// name, built from the event structs s.t. new fields are covered
+var fieldKinds = func() map[string]fieldKind {
+
+	kinds := make(map[string]fieldKind)
+
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous {
+				walk(f.Type)
+				continue
+			}
+
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+
+			switch {
+			case f.Type == reflect.TypeOf(OptInt{}):
+				kinds[name] = kindInt
+			case f.Type.Kind() == reflect.Int || f.Type.Kind() == reflect.Int64:
+				kinds[name] = kindInt
+			case f.Type.Kind() == reflect.Float32 || f.Type.Kind() == reflect.Float64:
+				kinds[name] = kindFloat
+			case f.Type.Kind() == reflect.String:
+				kinds[name] = kindString
+			}
+		}
+	}
+
+	for _, e := range []interface{}{StopEvent{}, DoorEvent{}, TrafficLightEvent{}, SignOnEvent{}} {
+		walk(reflect.TypeOf(e))
+	}
+
+	return kinds
+}()
+
+// coerce - converts v to the kind expected, returns the converted value and the
+// variant converted from, e.g. `string->int`, or "" if v is already of the right
+// kind; ok is false if v can't be converted. Empty strings are treated as `null`
+func coerce(v interface{}, want fieldKind) (out interface{}, variant string, ok bool) {
+
+	switch v := v.(type) {
+	case nil:
+		return nil, "", true
+
+	case json.Number:
+		switch want {
+		case kindString:
+			return v.String(), "number->string", true
+		case kindInt:
+			if _, err := v.Int64(); err == nil {
+				return v, "", true
+			}
+			// e.g. `"veh": 423.0`
+			if f, err := v.Float64(); err == nil && f == math.Trunc(f) {
+				return int64(f), "float->int", true
+			}
+			return v, "", false
+		default:
+			return v, "", true
+		}
+
+	case string:
+		s := strings.TrimSpace(v)
+
+		switch want {
+		case kindString:
+			return v, "", true
+		case kindInt:
+			if s == "" {
+				return nil, "string->null", true
+			}
+			// e.g. `"veh": "00423"`
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return i, "string->int", true
+			}
+			return v, "", false
+		default:
+			if s == "" {
+				return nil, "string->null", true
+			}
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f, "string->float", true
+			}
+			return v, "", false
+		}
+	}
+
+	return v, "", false
+}
+
+// decodeTolerant - decodes a body that failed to decode strictly, converting fields
+// sent w. a known type variant (e.g. a route sent as `123` rather than `"123"`, or a
+// vehicle as `"00423"` rather than `423`) to the expected type. Returns the fields
+// converted, e.g. `route:number->string`, or errNothingCoerced if no field needed
+// converting (i.e. the body is broken some other way)
+//
+// NOTE: Only called once ffjson has failed, well-formed bodies keep the fast path
+func decodeTolerant(msgb []byte, hold *EventHolder) ([]string, error) {
+
+	var body map[string]map[string]interface{}
+
+	d := json.NewDecoder(bytes.NewReader(msgb))
+	d.UseNumber()
+
+	if err := d.Decode(&body); err != nil {
+		return nil, err
+	}
+
+	var coerced []string
+
+	for _, fields := range body {
+		for name, v := range fields {
+
+			want, ok := fieldKinds[name]
+			if !ok {
+				continue
+			}
+
+			out, variant, ok := coerce(v, want)
+			if !ok || variant == "" {
+				continue
+			}
+
+			fields[name] = out
+			coerced = append(coerced, fmt.Sprintf("%s:%s", name, variant))
+		}
+	}
+
+	if len(coerced) == 0 {
+		return nil, errNothingCoerced
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	*hold = EventHolder{}
+	if err := ffjson.Unmarshal(b, hold); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(coerced)
+	return coerced, nil
+}
@@ -0,0 +1,121 @@
+package hsldatabridge
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeTolerant(t *testing.T) {
+
+	for _, tc := range []struct {
+		name    string
+		body    string
+		coerced []string
+		check   func(h *EventHolder) bool
+	}{
+		{
+			name:    "zero padded vehicle",
+			body:    `{"VP":{"veh":"00423","route":"2159"}}`,
+			coerced: []string{"veh:string->int"},
+			check:   func(h *EventHolder) bool { return h.VP.VehID == 423 },
+		},
+		{
+			name:    "numeric route",
+			body:    `{"VP":{"veh":423,"route":2159}}`,
+			coerced: []string{"route:number->string"},
+			check:   func(h *EventHolder) bool { return h.VP.RouteID == "2159" },
+		},
+		{
+			name:    "whole float vehicle",
+			body:    `{"VP":{"veh":423.0}}`,
+			coerced: []string{"veh:float->int"},
+			check:   func(h *EventHolder) bool { return h.VP.VehID == 423 },
+		},
+		{
+			name:    "string speed",
+			body:    `{"VP":{"veh":423,"spd":" 8.5"}}`,
+			coerced: []string{"spd:string->float"},
+			check:   func(h *EventHolder) bool { return h.VP.Spd == 8.5 },
+		},
+		{
+			name:    "empty stop is null",
+			body:    `{"ARR":{"veh":423,"stop":""}}`,
+			coerced: []string{"stop:string->null"},
+			check:   func(h *EventHolder) bool { return h.ARR != nil && !h.ARR.Stop.Valid },
+		},
+		{
+			name:    "empty speed is null",
+			body:    `{"VP":{"veh":423,"spd":""}}`,
+			coerced: []string{"spd:string->null"},
+			check:   func(h *EventHolder) bool { return h.VP.Spd == 0 },
+		},
+		{
+			name:    "several fields, sorted",
+			body:    `{"DOO":{"veh":"00423","route":550,"drst":"1","stop":null}}`,
+			coerced: []string{"drst:string->int", "route:number->string", "veh:string->int"},
+			check: func(h *EventHolder) bool {
+				d := h.DOO
+				return d != nil && d.VehID == 423 && d.RouteID == "550" && d.DoorStatus == OptInt{Value: 1, Valid: true} && !d.Stop.Valid
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &EventHolder{}
+
+			coerced, err := decodeTolerant([]byte(tc.body), h)
+			if err != nil {
+				t.Fatalf("decodeTolerant(%s): %+v", tc.body, err)
+			}
+
+			if !reflect.DeepEqual(coerced, tc.coerced) {
+				t.Errorf("decodeTolerant(%s): got coerced %q, want %q", tc.body, coerced, tc.coerced)
+			}
+
+			if h.Event() == nil || !tc.check(h) {
+				t.Errorf("decodeTolerant(%s): unexpected event %+v", tc.body, h.Event())
+			}
+		})
+	}
+}
+
+func TestDecodeTolerantFails(t *testing.T) {
+
+	for _, body := range []string{
+		`{"VP":{"veh":true}}`,     // No known variant for a bool
+		`{"VP":{"veh":"abc"}}`,    // Not a number
+		`{"VP":{"veh":423.5}}`,    // Not a whole number
+		`{"VP":{"veh":423}}`,      // Nothing to convert
+		`{"VP":{"veh":"00423"}`,   // Not JSON
+		`{"VP":["veh", "00423"]}`, // Not an event
+	} {
+		if coerced, err := decodeTolerant([]byte(body), &EventHolder{}); err == nil {
+			t.Errorf("decodeTolerant(%s): got coerced %q, want error", body, coerced)
+		}
+	}
+}
+
+func TestDeserializeOptionalFields(t *testing.T) {
+
+	const topic = "/hfp/v2/journey/ongoing/dep/bus/0018/00423/2159/2/Matinkylä (M)/09:32/2442201/3/60;24/16/58/67"
+
+	for _, tc := range []struct {
+		body string
+		stop OptInt
+		odo  OptInt
+	}{
+		{`{"DEP":{"veh":423,"stop":null,"odo":null}}`, OptInt{}, OptInt{}},
+		{`{"DEP":{"veh":423}}`, OptInt{}, OptInt{}},
+		{`{"DEP":{"veh":423,"stop":0,"odo":1200}}`, OptInt{Value: 0, Valid: true}, OptInt{Value: 1200, Valid: true}},
+		{`{"DEP":{"veh":"00423","stop":"","odo":"1200"}}`, OptInt{}, OptInt{Value: 1200, Valid: true}},
+	} {
+		h := &EventHolder{}
+		if err := DeserializeMQTTBody(topic, []byte(tc.body), h); err != nil {
+			t.Errorf("DeserializeMQTTBody(%s): %+v", tc.body, err)
+			continue
+		}
+
+		if e := h.Event(); e.Stop != tc.stop || e.Odometer != tc.odo || e.VehID != 423 {
+			t.Errorf("DeserializeMQTTBody(%s): got stop %+v, odo %+v, veh %d; want %+v, %+v, 423", tc.body, e.Stop, e.Odometer, e.VehID, tc.stop, tc.odo)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package hsldatabridge
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// RuleCode - identifies the rule that rejected an event, returned as the
+// reason code of an MQTTValidationError
+type RuleCode string
+
+// Rule codes, also the names used to enable rules w. `validation.rules`
+const (
+	RuleRequired    RuleCode = "required"
+	RuleBoundingBox RuleCode = "bbox"
+	RuleMaxSpeed    RuleCode = "speed"
+	RuleMaxAcc      RuleCode = "acc"
+	RuleSkew        RuleCode = "skew"
+	RuleHeading     RuleCode = "heading"
+)
+
+// Rule - A single check on a decoded event, returns nil if the event passes
+type Rule interface {
+	Code() RuleCode
+	Check(e *EventHolder, received time.Time) *MQTTValidationError
+}
+
+// reject - formats a rejection for rule c
+func reject(c RuleCode, format string, args ...interface{}) *MQTTValidationError {
+	return &MQTTValidationError{
+		Message: fmt.Sprintf("Custom error; %s: %s", c, fmt.Sprintf(format, args...)),
+		Rule:    c,
+	}
+}
+
+// BoundingBoxRule - Rejects events located outside the box, events w.o. a
+// location (e.g. door events w. `loc` set to N/A) pass
+type BoundingBoxRule struct {
+	MinLat, MinLng float64
+	MaxLat, MaxLng float64
+}
+
+// Code -
+func (r *BoundingBoxRule) Code() RuleCode { return RuleBoundingBox }
+
+// Check -
+func (r *BoundingBoxRule) Check(e *EventHolder, received time.Time) *MQTTValidationError {
+
+	ev := e.Event()
+	if ev.Lat == 0.0 && ev.Lng == 0.0 {
+		return nil
+	}
+
+	if ev.Lat < r.MinLat || ev.Lat > r.MaxLat || ev.Lng < r.MinLng || ev.Lng > r.MaxLng {
+		return reject(RuleBoundingBox, "(%f, %f) outside service area", ev.Lat, ev.Lng)
+	}
+
+	return nil
+}
+
+// MaxSpeedRule - Rejects events w. a speed above Max (m/s)
+type MaxSpeedRule struct {
+	Max float32
+}
+
+// Code -
+func (r *MaxSpeedRule) Code() RuleCode { return RuleMaxSpeed }
+
+// Check -
+func (r *MaxSpeedRule) Check(e *EventHolder, received time.Time) *MQTTValidationError {
+	if spd := e.Event().Spd; spd > r.Max || spd < 0 {
+		return reject(RuleMaxSpeed, "speed %.2f m/s outside [0, %.2f]", spd, r.Max)
+	}
+	return nil
+}
+
+// MaxAccRule - Rejects events w. an acceleration (or deceleration) above Max (m/s^2)
+type MaxAccRule struct {
+	Max float32
+}
+
+// Code -
+func (r *MaxAccRule) Code() RuleCode { return RuleMaxAcc }
+
+// Check -
+func (r *MaxAccRule) Check(e *EventHolder, received time.Time) *MQTTValidationError {
+	if acc := e.Event().Acc; acc > r.Max || acc < -r.Max {
+		return reject(RuleMaxAcc, "acceleration %.2f m/s^2 outside [-%.2f, %.2f]", acc, r.Max, r.Max)
+	}
+	return nil
+}
+
+// SkewRule - Rejects events where the vehicle's timestamp (`tsi`) is more
+// than Max from the time the message was received
+type SkewRule struct {
+	Max time.Duration
+}
+
+// Code -
+func (r *SkewRule) Code() RuleCode { return RuleSkew }
+
+// Check -
+func (r *SkewRule) Check(e *EventHolder, received time.Time) *MQTTValidationError {
+
+	ts := e.Event().Timestamp
+	if ts == 0 || received.IsZero() {
+		return nil
+	}
+
+	skew := received.Sub(time.Unix(ts, 0))
+	if skew > r.Max || skew < -r.Max {
+		return reject(RuleSkew, "timestamp %d is %s from receive time", ts, skew.Round(time.Second))
+	}
+
+	return nil
+}
+
+// HeadingRule - Rejects events w. a heading outside [Min, Max] degrees
+type HeadingRule struct {
+	Min, Max int
+}
+
+// Code -
+func (r *HeadingRule) Code() RuleCode { return RuleHeading }
+
+// Check -
+func (r *HeadingRule) Check(e *EventHolder, received time.Time) *MQTTValidationError {
+	if hdg := e.Event().Heading; hdg < r.Min || hdg > r.Max {
+		return reject(RuleHeading, "heading %d outside [%d, %d]", hdg, r.Min, r.Max)
+	}
+	return nil
+}
+
+// requiredFields - reports whether each field (by JSON name) is set on the event,
+// `route` and `veh` are usually filled in from the topic
+var requiredFields = map[string]func(e *Event) bool{
+	"jrn":   func(e *Event) bool { return e.JrnID != 0 },
+	"oday":  func(e *Event) bool { return e.ODay != "" },
+	"veh":   func(e *Event) bool { return e.VehID != 0 },
+	"tsi":   func(e *Event) bool { return e.Timestamp != 0 },
+	"lat":   func(e *Event) bool { return e.Lat != 0.0 },
+	"long":  func(e *Event) bool { return e.Lng != 0.0 },
+	"route": func(e *Event) bool { return e.RouteID != "" },
+	"stop":  func(e *Event) bool { return e.Stop.Valid },
+}
+
+// RequiredRule - Rejects events missing any of the fields required for their
+// type; types w.o. an entry in Fields are not checked
+type RequiredRule struct {
+	Fields map[EventType][]string
+}
+
+// Code -
+func (r *RequiredRule) Code() RuleCode { return RuleRequired }
+
+// Check -
+func (r *RequiredRule) Check(e *EventHolder, received time.Time) *MQTTValidationError {
+	for _, field := range r.Fields[e.Type()] {
+		if isSet, ok := requiredFields[field]; ok && !isSet(e.Event()) {
+			return reject(RuleRequired, "%s event missing %s", e.Type(), field)
+		}
+	}
+	return nil
+}
+
+// ParseRequiredFields - parses the fields required for each event type from a
+// semicolon separated list of `types=fields` entries, types and fields comma
+// separated, e.g. positions must have a location and stop events a stop:
+//
+// VP=tsi,lat,long;DUE,ARR,DEP,ARS,PDE,PAS,WAIT=tsi,stop
+func ParseRequiredFields(s string) (map[EventType][]string, error) {
+
+	fields := make(map[EventType][]string)
+
+	for _, entry := range strings.Split(s, ";") {
+
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid required fields %q, want types=fields", entry)
+		}
+
+		var names []string
+		for _, f := range strings.Split(parts[1], ",") {
+			if f = strings.TrimSpace(f); f == "" {
+				continue
+			}
+			if requiredFields[f] == nil {
+				return nil, fmt.Errorf("unknown required field %q", f)
+			}
+			names = append(names, f)
+		}
+
+		for _, t := range strings.Split(parts[0], ",") {
+			et := EventType(strings.ToUpper(strings.TrimSpace(t)))
+			if !eventTypes[et] {
+				return nil, fmt.Errorf("unknown event type %q", t)
+			}
+			fields[et] = append(fields[et], names...)
+		}
+	}
+
+	return fields, nil
+}
+
+// Validator - Runs each rule, in order, against decoded events and counts the
+// events rejected by each rule
+type Validator struct {
+	rules    []Rule
+	rejected map[RuleCode]*uint64
+}
+
+// NewValidator - creates a validator running rules in the order given
+func NewValidator(rules ...Rule) *Validator {
+
+	v := &Validator{
+		rules:    rules,
+		rejected: make(map[RuleCode]*uint64, len(rules)),
+	}
+
+	for _, r := range rules {
+		var n uint64
+		v.rejected[r.Code()] = &n
+	}
+
+	return v
+}
+
+// Validate - returns the first rule violation as an MQTTValidationError, or nil
+// if the event passes every rule
+func (v *Validator) Validate(e *EventHolder, received time.Time) error {
+	for _, r := range v.rules {
+		if err := r.Check(e, received); err != nil {
+			atomic.AddUint64(v.rejected[r.Code()], 1)
+			return err
+		}
+	}
+	return nil
+}
+
+// Rejected - returns the count of events rejected by each rule
+func (v *Validator) Rejected() map[RuleCode]uint64 {
+
+	counts := make(map[RuleCode]uint64, len(v.rejected))
+	for c, n := range v.rejected {
+		counts[c] = atomic.LoadUint64(n)
+	}
+
+	return counts
+}
+
+// NewValidatorFromConfig - creates a validator running the rules named in
+// cfg.Rules, in order, w. the limits in cfg
+func NewValidatorFromConfig(cfg ValidationConfig) (*Validator, error) {
+
+	var bbox [4]float64
+
+	parts := strings.Split(cfg.BBox, ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid bbox %q, want minLat,minLng,maxLat,maxLng", cfg.BBox)
+	}
+
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bbox %q: %w", cfg.BBox, err)
+		}
+		bbox[i] = f
+	}
+
+	required, err := ParseRequiredFields(cfg.Required)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.HeadingMin > cfg.HeadingMax {
+		return nil, fmt.Errorf("invalid heading range [%d, %d]", cfg.HeadingMin, cfg.HeadingMax)
+	}
+
+	available := map[RuleCode]Rule{
+		RuleRequired:    &RequiredRule{Fields: required},
+		RuleBoundingBox: &BoundingBoxRule{MinLat: bbox[0], MinLng: bbox[1], MaxLat: bbox[2], MaxLng: bbox[3]},
+		RuleMaxSpeed:    &MaxSpeedRule{Max: float32(cfg.MaxSpd)},
+		RuleMaxAcc:      &MaxAccRule{Max: float32(cfg.MaxAcc)},
+		RuleSkew:        &SkewRule{Max: cfg.Skew},
+		RuleHeading:     &HeadingRule{Min: cfg.HeadingMin, Max: cfg.HeadingMax},
+	}
+
+	var rules []Rule
+	for _, name := range strings.Split(cfg.Rules, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+
+		r, ok := available[RuleCode(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown validation rule %q", name)
+		}
+		rules = append(rules, r)
+	}
+
+	return NewValidator(rules...), nil
+}
@@ -0,0 +1,140 @@
+package hsldatabridge
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRules(t *testing.T) {
+
+	received := time.Unix(1620979200, 0)
+
+	vp := func(e Event) *EventHolder { return &EventHolder{VP: &e} }
+	arr := func(e Event) *EventHolder { return &EventHolder{ARR: &StopEvent{Event: e}} }
+
+	required, err := ParseRequiredFields(DefaultConfig().Validation.Required)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		requiredRule = &RequiredRule{Fields: required}
+		bboxRule     = &BoundingBoxRule{MinLat: 59.8, MinLng: 23.8, MaxLat: 60.8, MaxLng: 25.8}
+		speedRule    = &MaxSpeedRule{Max: 50}
+		accRule      = &MaxAccRule{Max: 10}
+		skewRule     = &SkewRule{Max: 5 * time.Minute}
+		headingRule  = &HeadingRule{Min: 0, Max: 360}
+	)
+
+	for _, tc := range []struct {
+		name   string
+		rule   Rule
+		e      *EventHolder
+		reject bool
+	}{
+		{"required: position", requiredRule, vp(Event{Timestamp: 1, Lat: 60.17, Lng: 24.94}), false},
+		{"required: position w.o. coords", requiredRule, vp(Event{Timestamp: 1, Lat: 60.17}), true},
+		{"required: stop event", requiredRule, arr(Event{Timestamp: 1, Stop: OptInt{Value: 1020602, Valid: true}}), false},
+		{"required: stop event w. stop 0", requiredRule, arr(Event{Timestamp: 1, Stop: OptInt{Valid: true}}), false},
+		{"required: stop event w.o. stop", requiredRule, arr(Event{Timestamp: 1}), true},
+		{"required: unchecked type", requiredRule, &EventHolder{DOO: &DoorEvent{}}, false},
+
+		{"bbox: inside", bboxRule, vp(Event{Lat: 60.17, Lng: 24.94}), false},
+		{"bbox: on the min edge", bboxRule, vp(Event{Lat: 59.8, Lng: 23.8}), false},
+		{"bbox: on the max edge", bboxRule, vp(Event{Lat: 60.8, Lng: 25.8}), false},
+		{"bbox: south", bboxRule, vp(Event{Lat: 59.79, Lng: 24.94}), true},
+		{"bbox: east", bboxRule, vp(Event{Lat: 60.17, Lng: 25.81}), true},
+		{"bbox: no location", bboxRule, vp(Event{}), false},
+
+		{"speed: moving", speedRule, vp(Event{Spd: 12.5}), false},
+		{"speed: at max", speedRule, vp(Event{Spd: 50}), false},
+		{"speed: above max", speedRule, vp(Event{Spd: 50.5}), true},
+		{"speed: negative", speedRule, vp(Event{Spd: -0.1}), true},
+
+		{"acc: at max", accRule, vp(Event{Acc: 10}), false},
+		{"acc: at max braking", accRule, vp(Event{Acc: -10}), false},
+		{"acc: above max", accRule, vp(Event{Acc: 10.5}), true},
+		{"acc: above max braking", accRule, vp(Event{Acc: -10.5}), true},
+
+		{"skew: none", skewRule, vp(Event{Timestamp: received.Unix()}), false},
+		{"skew: max behind", skewRule, vp(Event{Timestamp: received.Add(-5 * time.Minute).Unix()}), false},
+		{"skew: max ahead", skewRule, vp(Event{Timestamp: received.Add(5 * time.Minute).Unix()}), false},
+		{"skew: behind", skewRule, vp(Event{Timestamp: received.Add(-6 * time.Minute).Unix()}), true},
+		{"skew: ahead", skewRule, vp(Event{Timestamp: received.Add(6 * time.Minute).Unix()}), true},
+		{"skew: no timestamp", skewRule, vp(Event{}), false},
+
+		{"heading: north", headingRule, vp(Event{Heading: 0}), false},
+		{"heading: 360", headingRule, vp(Event{Heading: 360}), false},
+		{"heading: above 360", headingRule, vp(Event{Heading: 361}), true},
+		{"heading: negative", headingRule, vp(Event{Heading: -1}), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.Check(tc.e, received)
+
+			if tc.reject && (err == nil || err.Rule != tc.rule.Code()) {
+				t.Errorf("got %v, want rejection by %s", err, tc.rule.Code())
+			}
+
+			if !tc.reject && err != nil {
+				t.Errorf("got %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidatorCountsRejections(t *testing.T) {
+
+	v := NewValidator(&HeadingRule{Min: 0, Max: 360}, &MaxSpeedRule{Max: 50})
+
+	for _, e := range []Event{{Heading: 400, Spd: 60}, {Spd: 60}, {}} {
+		v.Validate(&EventHolder{VP: &e}, time.Time{})
+	}
+
+	// Stops at the first rule broken
+	want := map[RuleCode]uint64{RuleHeading: 1, RuleMaxSpeed: 1}
+	if got := v.Rejected(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got rejected %v, want %v", got, want)
+	}
+}
+
+func TestParseRequiredFields(t *testing.T) {
+
+	got, err := ParseRequiredFields(" vp=tsi, lat ; DUE,ARR=stop;")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[EventType][]string{
+		EventTypeVP:  {"tsi", "lat"},
+		EventTypeDUE: {"stop"},
+		EventTypeARR: {"stop"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for _, s := range []string{"VP", "VP=tsi,colour", "XYZ=tsi"} {
+		if _, err := ParseRequiredFields(s); err == nil {
+			t.Errorf("ParseRequiredFields(%q): got nil error", s)
+		}
+	}
+}
+
+func TestNewValidatorFromConfig(t *testing.T) {
+
+	cfg := DefaultConfig().Validation
+	cfg.Rules, cfg.HeadingMin, cfg.HeadingMax = "heading", 90, 180
+
+	v, err := NewValidatorFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for hdg, reject := range map[int]bool{89: true, 90: false, 180: false, 181: true} {
+		if err := v.Validate(&EventHolder{VP: &Event{Heading: hdg}}, time.Time{}); (err != nil) != reject {
+			t.Errorf("heading %d: got %v, want rejected %t", hdg, err, reject)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package hsldatabridge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	redis "github.com/go-redis/redis/v8"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// vehicleGeoKey - geo set of vehicles by their latest position, members are
+	// vehicle IDs, e.g. `0018/00423`
+	vehicleGeoKey = "vehicles:geo"
+
+	// vehicleLastSeenKey - sorted set of vehicles scored by the time (unix ms) of
+	// their latest position, used to evict stale vehicles from vehicleGeoKey
+	vehicleLastSeenKey = "vehicles:lastseen"
+
+	// vehicleEvictBatch - max stale vehicles evicted per call to evictVehiclesScript
+	vehicleEvictBatch = 1000
+)
+
+// evictVehiclesScript - removes vehicles last seen before a cutoff from the geo and
+// last-seen sets, returns the number removed; their hashes expire on their own
+//
+// KEYS: lastseen zset, geo set
+// ARGV: cutoff (ms), max vehicles
+var evictVehiclesScript = redis.NewScript(`
+local stale = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+if #stale == 0 then
+	return 0
+end
+
+redis.call('ZREM', KEYS[1], unpack(stale))
+redis.call('ZREM', KEYS[2], unpack(stale))
+return #stale
+`)
+
+// VehicleStateKey - returns the key of the vehicle's live state hash, e.g.
+// `vehicle:0018/00423`
+func VehicleStateKey(vehicleID string) string {
+	return "vehicle:" + vehicleID
+}
+
+// VehicleID - the operator and vehicle number of the event's vehicle, e.g. `0018/00423`,
+// same as VehicleKey for its topic
+func VehicleID(e *EventHolder) string {
+	return e.Topic.OperatorID + "/" + e.Topic.VehicleNumber
+}
+
+// VehicleStore - The live state of the fleet, i.e. where each vehicle is right now.
+// Each vehicle's latest position is kept in a hash (see VehicleStateKey), and the
+// vehicle is indexed by position in a geo set. Vehicles w.o. a position for IdleAfter
+// are evicted; state is kept in Redis s.t. any service can read it
+type VehicleStore struct {
+	IdleAfter time.Duration
+
+	client *redis.Client
+}
+
+// NewVehicleStore - creates a store evicting vehicles idle for cfg.IdleAfter
+func NewVehicleStore(client *redis.Client, cfg VehiclesConfig) *VehicleStore {
+	return &VehicleStore{IdleAfter: cfg.IdleAfter, client: client}
+}
+
+// Update - sets the vehicle's state to the position, added to the sink's pipeline;
+// ts is the vehicle's timestamp (unix ms). The hash expires IdleAfter after the
+// vehicle's latest position, the geo set is cleaned up by Evict
+func (vs *VehicleStore) Update(ctx context.Context, pipe redis.Pipeliner, e *EventHolder, journeyID string, ts int64) {
+
+	vehicleID := VehicleID(e)
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+
+	pipe.HSet(
+		ctx, VehicleStateKey(vehicleID),
+		"lat", e.VP.Lat,
+		"lng", e.VP.Lng,
+		"hdg", e.VP.Heading,
+		"spd", e.VP.Spd,
+		"dl", e.VP.DeltaToSchedule,
+		"rt", e.VP.RouteID,
+		"dir", e.VP.Direction,
+		"mode", e.Topic.TransportMode,
+		"hdsg", e.Topic.Headsign,
+		"jid", journeyID,
+		"ts", ts,
+		"seen", now,
+	)
+	pipe.PExpire(ctx, VehicleStateKey(vehicleID), vs.IdleAfter)
+
+	pipe.GeoAdd(ctx, vehicleGeoKey, &redis.GeoLocation{
+		Name: vehicleID, Longitude: e.VP.Lng, Latitude: e.VP.Lat,
+	})
+	pipe.ZAdd(ctx, vehicleLastSeenKey, &redis.Z{Score: float64(now), Member: vehicleID})
+}
+
+// Evict - removes vehicles w.o. a position for IdleAfter from the geo index, up to
+// vehicleEvictBatch per call; returns the number evicted
+func (vs *VehicleStore) Evict(ctx context.Context) (int, error) {
+
+	cutoff := time.Now().Add(-vs.IdleAfter).UnixNano() / int64(time.Millisecond)
+
+	n, err := evictVehiclesScript.Run(
+		ctx, vs.client, []string{vehicleLastSeenKey, vehicleGeoKey},
+		strconv.FormatInt(cutoff, 10), vehicleEvictBatch,
+	).Int()
+
+	if err != nil {
+		return 0, err
+	}
+
+	vehiclesEvicted.Add(float64(n))
+	return n, nil
+}
+
+// Run - evicts stale vehicles every interval until ctx is cancelled
+func (vs *VehicleStore) Run(ctx context.Context, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := vs.Evict(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Errorf("Failed to Evict Vehicles: %+v", err)
+			}
+			if n > 0 {
+				log.WithFields(log.Fields{"Vehicles": n}).Debug("Evicted Stale Vehicles")
+			}
+		}
+	}
+}
+
+// Vehicle - A vehicle's live state, see VehicleStore.Update
+type Vehicle struct {
+	ID        string  `json:"id"`   // Operator and vehicle number, e.g. `0018/00423`
+	Dist      float64 `json:"dist"` // Distance from the search point, meters
+	Lat       float64 `json:"lat"`
+	Lng       float64 `json:"lng"`
+	Heading   int     `json:"hdg"`
+	Spd       float64 `json:"spd"`
+	Delay     float64 `json:"dl"`
+	RouteID   string  `json:"route"`
+	Direction string  `json:"dir"`
+	Mode      string  `json:"mode"`
+	Headsign  string  `json:"headsign"`
+	JourneyID string  `json:"jid"`
+	Timestamp int64   `json:"ts"`   // Vehicle's timestamp of the position, unix ms
+	Seen      int64   `json:"seen"` // Time the position was written, unix ms
+}
+
+// NearbyQuery - Vehicles w.in Radius (meters) of a point, optionally only those
+// w. one of Modes and/or on one of Routes; at most Limit are returned
+type NearbyQuery struct {
+	Lat, Lng float64
+	Radius   float64
+	Modes    []string
+	Routes   []string
+	Limit    int
+}
+
+// nearbyOverscan - factor the GEOSEARCH COUNT grows by when too few of the vehicles
+// found match a NearbyQuery, see NearbyVehicles
+const nearbyOverscan = 4
+
+// NearbyVehicles - returns the live state of the vehicles matching q, nearest first.
+// Searches the geo index w. GEOSEARCH (Redis 6.2+) for the nearest Limit vehicles, then
+// reads their hashes in a single pipeline. Vehicles are filtered by mode && route after
+// the search; while too few match (or some have expired), the search is repeated for
+// nearbyOverscan times as many vehicles, reading only the hashes not yet read, s.t.
+// Limit applies to the matching vehicles
+func NearbyVehicles(ctx context.Context, client *redis.Client, q NearbyQuery) ([]*Vehicle, error) {
+
+	anyOf := func(v string, in []string) bool {
+		if len(in) == 0 {
+			return true
+		}
+		for _, s := range in {
+			if s == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	vehicles := make([]*Vehicle, 0)
+	read, count := 0, q.Limit
+
+	for {
+		args := []interface{}{
+			"GEOSEARCH", vehicleGeoKey, "FROMLONLAT", q.Lng, q.Lat, "BYRADIUS", q.Radius, "m", "ASC", "WITHDIST",
+		}
+		if count > 0 {
+			args = append(args, "COUNT", count)
+		}
+
+		result, err := client.Do(ctx, args...).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		// Each reply is [member, dist], nearest first; the first `read` were read
+		// by a previous search
+		matches := result.([]interface{})
+		if read >= len(matches) {
+			return vehicles, nil
+		}
+		matches = matches[read:]
+
+		dists := make([]float64, len(matches))
+
+		pipe := client.Pipeline()
+		cmds := make([]*redis.StringStringMapCmd, len(matches))
+
+		for i, m := range matches {
+			r := m.([]interface{})
+			if dists[i], err = strconv.ParseFloat(r[1].(string), 64); err != nil {
+				return nil, err
+			}
+			cmds[i] = pipe.HGetAll(ctx, VehicleStateKey(r[0].(string)))
+		}
+
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+
+		for i, cmd := range cmds {
+
+			// Expired, not yet evicted from the index
+			state := cmd.Val()
+			if len(state) == 0 {
+				continue
+			}
+
+			if !anyOf(state["mode"], q.Modes) || !anyOf(state["rt"], q.Routes) {
+				continue
+			}
+
+			v, err := parseVehicle(matches[i].([]interface{})[0].(string), state)
+			if err != nil {
+				return nil, err
+			}
+			v.Dist = dists[i]
+
+			if vehicles = append(vehicles, v); q.Limit > 0 && len(vehicles) == q.Limit {
+				return vehicles, nil
+			}
+		}
+
+		// Every vehicle w.in the radius was read
+		if count <= 0 || read+len(matches) < count {
+			return vehicles, nil
+		}
+
+		read, count = count, count*nearbyOverscan
+	}
+}
+
+// parseVehicle - reads a vehicle's state from the hash written by VehicleStore.Update
+func parseVehicle(id string, state map[string]string) (*Vehicle, error) {
+
+	var err error
+
+	float := func(k string) float64 {
+		f, e := strconv.ParseFloat(state[k], 64)
+		if e != nil && err == nil {
+			err = fmt.Errorf("vehicle %s: %s: %w", id, k, e)
+		}
+		return f
+	}
+
+	integer := func(k string) int64 {
+		n, e := strconv.ParseInt(state[k], 10, 64)
+		if e != nil && err == nil {
+			err = fmt.Errorf("vehicle %s: %s: %w", id, k, e)
+		}
+		return n
+	}
+
+	v := &Vehicle{
+		ID:        id,
+		Lat:       float("lat"),
+		Lng:       float("lng"),
+		Heading:   int(integer("hdg")),
+		Spd:       float("spd"),
+		Delay:     float("dl"),
+		RouteID:   state["rt"],
+		Direction: state["dir"],
+		Mode:      state["mode"],
+		Headsign:  state["hdsg"],
+		JourneyID: state["jid"],
+		Timestamp: integer("ts"),
+		Seen:      integer("seen"),
+	}
+
+	return v, err
+}
@@ -0,0 +1,228 @@
+package hsldatabridge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// minRetryBackoff, maxRetryBackoff - bounds on the wait between retries of a failed
+// write w. at-least-once delivery, see retry
+const (
+	minRetryBackoff = 100 * time.Millisecond
+	maxRetryBackoff = 10 * time.Second
+)
+
+// RunWorker - decodes each staged message and writes it to every sink, returns
+// once C is closed (or ctx is cancelled, dropping the batch); launch some workers here...
+//
+// Events are written in batches of up to batchSize, or whatever has been decoded
+// interval after the first event of the batch, s.t. sinks can write many events
+// per round-trip. W. at-least-once delivery, a message is acked only once every
+// sink has written its batch (or it can never be written, e.g. an invalid body).
+// Events are checked against v, if set; messages that fail to decode are written
+// to dlq, if set, s.t. they can be re-driven later
+func RunWorker(ctx context.Context, C <-chan *StagedMessage, sinks []Sink, v *Validator, dlq *DeadLetterQueue, batchSize int, interval time.Duration) {
+
+	var (
+		batch = make([]*EventHolder, 0, batchSize)
+		msgs  = make([]*StagedMessage, 0, batchSize)
+		timer = time.NewTimer(interval)
+		due   <-chan time.Time // nil, i.e. never ready, while the batch is empty
+	)
+
+	timer.Stop()
+
+	flush := func() {
+		writeBatch(ctx, sinks, batch, msgs)
+		batch, msgs = batch[:0], msgs[:0]
+
+		if due != nil && !timer.Stop() {
+			<-timer.C
+		}
+		due = nil
+	}
+
+	for {
+		select {
+		case msg, ok := <-C:
+			if !ok {
+				if len(batch) > 0 {
+					flush()
+				}
+				return
+			}
+
+			e := decodeMessage(ctx, msg, v, dlq)
+			if e == nil {
+				continue
+			}
+
+			batch, msgs = append(batch, e), append(msgs, msg)
+
+			if len(batch) >= batchSize {
+				flush()
+			} else if due == nil {
+				timer.Reset(interval)
+				due = timer.C
+			}
+
+		case <-due:
+			due = nil
+			flush()
+
+		// Shutdown timed out, w. at-least-once delivery the batch is left unacked
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// decodeMessage - decodes && validates the message, returns nil (having acked the
+// message, unless it couldn't be dead-lettered) if it can never be written
+func decodeMessage(ctx context.Context, msg *StagedMessage, v *Validator, dlq *DeadLetterQueue) *EventHolder {
+
+	// Receive the content of the MQTT message and de-serialize bytes into
+	// struct, the topic fills in anything missing from the body
+	e := &EventHolder{}
+
+	if err := DeserializeMQTTBody(msg.Topic, msg.Payload, e); err != nil {
+
+		// The entry was not deserializable into a known msg type, or its topic
+		// couldn't be parsed. Most often an error from the source feed, e.g the feed
+		// published a route as 123 instead of "123"; dead-lettered whatever the cause
+		decodeFailures.Inc()
+		log.WithFields(log.Fields{"Topic": msg.Topic}).Debugf("%+v", err)
+
+		if dlq != nil && !deadLetter(ctx, msg, err, dlq) {
+			return nil
+		}
+
+		// Redelivery wouldn't change the outcome, ack && move on
+		msg.Ack()
+		return nil
+	}
+
+	if v != nil {
+		if err := v.Validate(e, msg.Received); err != nil {
+
+			// Most common error is Missing or Bad Coords; See defn for
+			// `MQTTValidationError` for more...
+			fields := log.Fields{"Body": e}
+			if verr, ok := err.(*MQTTValidationError); ok {
+				fields["Rule"] = verr.Rule
+			}
+			log.WithFields(fields).Debugf("%+v", err)
+
+			msg.Ack()
+			return nil
+		}
+	}
+
+	if len(e.Coerced) > 0 {
+		log.WithFields(log.Fields{"Topic": msg.Topic, "Coerced": e.Coerced}).Debug("Coerced Fields")
+	}
+
+	return e
+}
+
+// deadLetter - pushes the message, w. the error it failed to decode w., to dlq;
+// reports whether it was written. W. at-least-once delivery the push is retried
+// (see retry) s.t. the message is acked only once it's dead-lettered, it's left
+// unacked (and redelivered on the next session) if shutdown cuts this short
+func deadLetter(ctx context.Context, msg *StagedMessage, cause error, dlq *DeadLetterQueue) bool {
+
+	push := func() error {
+		err := dlq.Push(ctx, msg, cause)
+		if err != nil {
+			log.WithFields(log.Fields{"Topic": msg.Topic}).Errorf("Failed to Dead-Letter Message: %+v", err)
+		}
+		return err
+	}
+
+	if mustAck(msg) {
+		return retry(ctx, push) == nil
+	}
+
+	// W. at-most-once delivery the message is lost either way
+	push()
+	return true
+}
+
+// writeBatch - writes the batch to every sink, acks msgs once all sinks succeed.
+// W. at-least-once delivery a failed write is retried w. backoff (only to the sinks
+// that failed) until it succeeds or ctx is cancelled: the broker only redelivers
+// unacked messages on a new session, left unacked they'd fill its in-flight window
+// and stall the subscription
+func writeBatch(ctx context.Context, sinks []Sink, batch []*EventHolder, msgs []*StagedMessage) {
+
+	pending := sinks
+
+	write := func() error {
+		var failed []Sink
+		var err error
+
+		for _, sink := range pending {
+			if serr := sink.Write(ctx, batch); serr != nil {
+				log.WithFields(
+					log.Fields{"Events": len(batch), "Sink": fmt.Sprintf("%T", sink)},
+				).Errorf("Failed to Write Batch: %+v", serr)
+				failed, err = append(failed, sink), serr
+			}
+		}
+
+		pending = failed
+		return err
+	}
+
+	var err error
+	if len(msgs) > 0 && mustAck(msgs[0]) {
+		err = retry(ctx, write)
+	} else {
+		err = write()
+	}
+
+	// W. at-least-once delivery, shutdown timed out; the messages are left unacked
+	// and redelivered by the broker when the session resumes
+	if err != nil {
+		return
+	}
+
+	for _, msg := range msgs {
+		msg.Ack()
+	}
+
+	log.WithFields(log.Fields{"Events": len(batch)}).Debug("Wrote Batch")
+}
+
+// mustAck - reports whether the message is left w. the broker until acked, i.e.
+// the client is running w. at-least-once delivery
+func mustAck(msg *StagedMessage) bool {
+	return msg.ack != nil
+}
+
+// retry - calls write until it succeeds or ctx is cancelled, waiting w. backoff
+// between attempts; returns the last error if ctx is cancelled first
+func retry(ctx context.Context, write func() error) error {
+
+	backoff := minRetryBackoff
+
+	for {
+		err := write()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package hsldatabridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakySink - a sink that fails its first `failures` writes
+type flakySink struct {
+	failures int
+	writes   int
+}
+
+func (s *flakySink) Write(ctx context.Context, batch []*EventHolder) error {
+	if s.writes++; s.writes <= s.failures {
+		return errors.New("sink unavailable")
+	}
+	return nil
+}
+
+func (s *flakySink) Flush(ctx context.Context) error { return nil }
+func (s *flakySink) Close() error                    { return nil }
+
+// ackedMessages - n messages as staged w. at-least-once delivery, counting acks
+func ackedMessages(n int, acks *int) []*StagedMessage {
+	msgs := make([]*StagedMessage, n)
+	for i := range msgs {
+		msgs[i] = &StagedMessage{ack: func() { *acks++ }}
+	}
+	return msgs
+}
+
+func TestWriteBatchRetries(t *testing.T) {
+
+	var acks int
+	ok, flaky := &flakySink{}, &flakySink{failures: 2}
+	batch := []*EventHolder{{VP: &Event{VehID: 423}}, {VP: &Event{VehID: 845}}}
+
+	writeBatch(context.Background(), []Sink{ok, flaky}, batch, ackedMessages(len(batch), &acks))
+
+	if acks != len(batch) {
+		t.Errorf("got %d acks, want %d", acks, len(batch))
+	}
+
+	// Only the failed sink is retried
+	if ok.writes != 1 || flaky.writes != 3 {
+		t.Errorf("got writes (%d, %d), want (1, 3)", ok.writes, flaky.writes)
+	}
+}
+
+func TestWriteBatchCancelled(t *testing.T) {
+
+	var acks int
+	sink := &flakySink{failures: 1 << 30}
+	batch := []*EventHolder{{VP: &Event{VehID: 423}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*minRetryBackoff)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		writeBatch(ctx, []Sink{sink}, batch, ackedMessages(len(batch), &acks))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("writeBatch didn't return once ctx was cancelled")
+	}
+
+	if acks != 0 || sink.writes < 2 {
+		t.Errorf("got %d acks after %d writes, want 0 acks after retries", acks, sink.writes)
+	}
+}
+
+func TestWriteBatchAtMostOnce(t *testing.T) {
+
+	// W.o. acks the batch is dropped after one attempt, not retried
+	sink := &flakySink{failures: 1}
+	batch := []*EventHolder{{VP: &Event{VehID: 423}}}
+
+	writeBatch(context.Background(), []Sink{sink}, batch, []*StagedMessage{{}})
+
+	if sink.writes != 1 {
+		t.Errorf("got %d writes, want 1", sink.writes)
+	}
+}
@@ -2,10 +2,36 @@ CREATE EXTENSION if not exists postgis; -- not sure why this didn't load?
 
 CREATE SCHEMA statistics;
 
+-- Written by hslservices/cmd/writebehind from the `events` stream, `id` is the
+-- stream entry ID; see hslservices/cmd/mqtt for the fields written for each type
 CREATE TABLE statistics.events (
-    id UUID NOT NULL, 
+    id varchar(32) NOT NULL,
     approx_event_time timestamp DEFAULT now(),
-    body JSONB NOT NULL,
+    event_time timestamp,
+    ev varchar(8) NOT NULL,
+    jid char(32),
+    route_id varchar,
+    mode varchar,
+    headsign varchar,
+    next_stop varchar,
+    lat double precision,
+    lng double precision,
+    spd real,
+    acc real,
+    dl real,
+    stop_id int,
+    ttarr timestamptz,
+    ttdep timestamptz,
+    drst smallint,
+    tlp_request_id int,
+    tlp_request_type varchar,
+    tlp_priority_level varchar,
+    tlp_reason varchar,
+    tlp_att_seq int,
+    tlp_decision varchar,
+    junction_id int,
+    signal_group_id int,
+    dr_type int,
     PRIMARY KEY (id)
 );
 
@@ -54,18 +80,43 @@ create materialized view event_log as (
     select
         id,
         approx_event_time,
-        st_setsrid(
-        	st_makepoint(
-	            (body ->> 'lng')::numeric, (body ->> 'lat')::numeric
-	        ),
-	        4326
-	    ) as geom,
-        (body ->> 'acc')::numeric as acc,
-        (body ->> 'dl')::numeric as dl,
-        (body ->> 'spd')::numeric as spd
+        st_setsrid(st_makepoint(lng, lat), 4326) as geom,
+        acc,
+        dl,
+        spd
     from "statistics"."events"
     where ((now() at time zone 'utc') - approx_event_time) < interval'1 hours'
+        and ev = 'VP'
 );
 
 create index on event_log using gist(geom);
 
+-- Non-position events share the events table, split by `ev` (event type)
+create view stop_event_log as (
+    select
+        id, approx_event_time, event_time, ev, jid, route_id, mode, stop_id, dl, ttarr, ttdep
+    from "statistics"."events"
+    where ev in ('DUE', 'ARR', 'DEP', 'ARS', 'PDE', 'PAS', 'WAIT')
+);
+
+create view door_event_log as (
+    select
+        id, approx_event_time, event_time, ev, jid, route_id, mode, stop_id, drst
+    from "statistics"."events"
+    where ev in ('DOO', 'DOC')
+);
+
+create view tlp_event_log as (
+    select
+        id, approx_event_time, event_time, ev, jid, route_id, mode,
+        tlp_request_id as request_id,
+        tlp_request_type as request_type,
+        tlp_priority_level as priority_level,
+        tlp_reason as reason,
+        tlp_att_seq as attempt_seq,
+        tlp_decision as decision,
+        junction_id,
+        signal_group_id
+    from "statistics"."events"
+    where ev in ('TLR', 'TLA')
+);